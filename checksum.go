@@ -0,0 +1,40 @@
+package main
+
+import (
+	"github.com/rs/zerolog/log"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// handleChecksum compares the checksum a client computed for its text with the
+// server's and pushes the server state to the client if they differ.
+// Checksums of versions the server no longer keeps can't be checked.
+func (s *session) handleChecksum(cl *client, sum *api_pb.Checksum) {
+	var expected uint32
+	switch version := s.history.Version(); {
+	case sum.Version > version:
+		s.resync(cl)
+		return
+	case sum.Version == version:
+		expected = s.text.Checksum()
+	default:
+		rev := s.history.At(sum.Version)
+		if rev == nil || rev.Checksum == 0 {
+			return
+		}
+		expected = rev.Checksum
+	}
+
+	if sum.Checksum != expected {
+		log.Warn().Str("document", s.doc.ID).Str("user", cl.id).Int32("version", sum.Version).Msg("client state diverged")
+		s.resync(cl)
+	}
+}
+
+// resync sends the client the whole current state of the document.
+func (s *session) resync(cl *client) {
+	cl.send(api_pb.Event_RESYNC, &api_pb.Resync{
+		Text:        s.text.String(),
+		LastVersion: s.history.Version(),
+		Checksum:    s.text.Checksum(),
+	})
+}
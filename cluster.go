@@ -0,0 +1,448 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/ssau-fiit/cloudocs-api/common/uuid"
+	"github.com/ssau-fiit/cloudocs-api/database"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"time"
+)
+
+// Several replicas can serve the same document. The one holding the lease in
+// leases.<id> is the sequencer of the document: it alone applies operations
+// and writes the operation log. Every other replica forwards the operations
+// of its clients to the sequencer over the channels.<id> pub/sub channel and
+// follows the revisions the sequencer publishes there. Replicas also publish
+// the participants and cursors of their own clients on the same channel.
+//
+// A replica can stop and start the session of a document while the old one
+// still holds the lease, so the lease and the messages belong to a session
+// token rather than to the replica.
+
+// instanceID tells the replicas apart.
+var instanceID = uuid.Must(uuid.NewV4()).String()
+
+// acquireLease takes the lease if it is free and extends it if the session
+// already holds it. It returns 1 if the session holds the lease afterwards.
+var acquireLease = redis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+if holder == false then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+elseif holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// releaseLease drops the lease if the session holds it.
+var releaseLease = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+const (
+	// kindSubmit forwards client operations to the sequencer.
+	kindSubmit = "submit"
+	// kindRevision announces a revision applied by the sequencer.
+	kindRevision = "revision"
+	// kindError reports a forwarded operation the sequencer could not apply.
+	kindError = "error"
+	// kindLeader announces a new sequencer, which makes the others forward
+	// again whatever they are still waiting for.
+	kindLeader = "leader"
+	// kindResign tells the others the sequencer gave up its lease, so one of
+	// them can take over without waiting for it to expire.
+	kindResign = "resign"
+	// kindPresence lists the participants connected to a replica.
+	kindPresence = "presence"
+	// kindCursor relays the cursor of a participant.
+	kindCursor = "cursor"
+	// kindCrdt relays the ops of a CRDT document applied by a replica.
+	kindCrdt = "crdt"
+	// kindDeleted tells the replicas the document was deleted.
+	kindDeleted = "deleted"
+	// kindSkipped answers forwarded operations that changed nothing, so
+	// no revision was made for them.
+	kindSkipped = "skipped"
+)
+
+// clusterMessage is published on the channel of a document. Only the fields
+// of its kind are set.
+type clusterMessage struct {
+	Kind string `json:"kind"`
+	// Instance is the token of the session that published the message, or
+	// the instanceID of the replica for messages from outside a session.
+	Instance string `json:"instance"`
+
+	UserID     string              `json:"user,omitempty"`
+	Connection string              `json:"connection,omitempty"`
+	ID         string              `json:"id,omitempty"`
+	Version    int32               `json:"version,omitempty"`
+	Unit       api_pb.PositionUnit `json:"unit,omitempty"`
+	Ops        []*api_pb.Operation `json:"ops,omitempty"`
+	Reverts    int32               `json:"reverts,omitempty"`
+
+	Revision *ot.Revision  `json:"revision,omitempty"`
+	Error    *api_pb.Error `json:"error,omitempty"`
+
+	Participants []*api_pb.Participant `json:"participants,omitempty"`
+	// Hello asks the other replicas to publish their presence.
+	Hello  bool           `json:"hello,omitempty"`
+	Cursor *api_pb.Cursor `json:"cursor,omitempty"`
+	// Crdt is an encoded CrdtUpdate, its oneofs don't survive JSON.
+	Crdt []byte `json:"crdt,omitempty"`
+}
+
+// pendingOp is an operation waiting to be sequenced.
+type pendingOp struct {
+	// client is the client that sent the operation, if any
+	client *client
+	// done is called with the resulting revision or the error that
+	// prevented applying the operation. Both are nil if the operation
+	// changed nothing.
+	done func(rev *ot.Revision, err error)
+
+	userID       string
+	connectionID string
+	ops          []*api_pb.Operation
+	version      int32
+	unit         api_pb.PositionUnit
+	// reverts is the version of the revision the operation undoes or
+	// redoes, if any
+	reverts int32
+}
+
+func leaseKey(docID string) string {
+	return fmt.Sprintf("leases.%v", docID)
+}
+
+func channelKey(docID string) string {
+	return fmt.Sprintf("channels.%v", docID)
+}
+
+// subscribe starts listening to the channels of every document.
+func subscribe() (*redis.PubSub, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	sub := database.Database().PSubscribe(ctx, channelKey("*"))
+	// wait for the subscription to be confirmed so nothing published after
+	// a document is loaded gets lost
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	return sub, nil
+}
+
+// publish sends m to every replica serving the document.
+func (s *session) publish(m *clusterMessage) {
+	m.Instance = s.token
+	publish(s.doc.ID, m)
+}
+
+// publish sends m to every replica serving the document docID.
+func publish(docID string, m *clusterMessage) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		log.Error().Err(err).Msg("error encoding cluster message")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := database.Database().Publish(ctx, channelKey(docID), data).Err(); err != nil {
+		log.Error().Err(err).Str("document", docID).Msg("error publishing cluster message")
+	}
+}
+
+// lead acquires or extends the lease of the document and reports whether this
+// session is its sequencer.
+func (s *session) lead() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	held, err := acquireLease.Run(ctx, database.Database(), []string{leaseKey(s.doc.ID)}, s.token, leaseTTL.Milliseconds()).Int()
+	if err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error acquiring lease")
+		return false
+	}
+	return held == 1
+}
+
+// resign gives up the lease so another replica can take over right away.
+func (s *session) resign() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	err := releaseLease.Run(ctx, database.Database(), []string{leaseKey(s.doc.ID)}, s.token).Err()
+	if err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error releasing lease")
+	}
+}
+
+// renew keeps the lease of the sequencer alive, or tries to take it over if
+// the sequencer went away.
+func (s *session) renew() {
+	leader := s.lead()
+	switch {
+	case leader && !s.leader:
+		log.Info().Str("document", s.doc.ID).Msg("became sequencer")
+		s.leader = true
+		s.reload()
+		s.publish(&clusterMessage{Kind: kindLeader})
+		for key, p := range s.pending {
+			delete(s.pending, key)
+			s.sequence(key.ID, p)
+		}
+	case !leader && s.leader:
+		log.Warn().Str("document", s.doc.ID).Msg("lost sequencer lease")
+		s.leader = false
+		// the new sequencer flushes the text from now on
+		if s.dirty {
+			s.dirty = false
+			dirtyDocuments.Add(-1)
+		}
+	}
+
+	if len(s.clients) > 0 {
+		s.publishPresence(false)
+	}
+	s.expirePresence()
+}
+
+// stop removes the local participants from the other replicas and hands the
+// lease over if this replica holds it.
+func (s *session) stop() {
+	if s.history == nil {
+		return
+	}
+	s.publish(&clusterMessage{Kind: kindPresence})
+	if s.leader {
+		s.resign()
+		s.publish(&clusterMessage{Kind: kindResign})
+	}
+}
+
+// sequence applies p if this replica is the sequencer and forwards it to the
+// sequencer otherwise. id identifies p across replicas.
+func (s *session) sequence(id string, p *pendingOp) {
+	if s.deleted {
+		p.done(nil, errDocumentDeleted)
+		return
+	}
+	if s.leader {
+		rev, err := s.apply(id, p)
+		if err != nil {
+			p.done(nil, err)
+			if errors.Is(err, errDocumentDeleted) {
+				s.closeDeleted()
+			}
+			return
+		}
+		if rev == nil {
+			p.done(nil, nil)
+			return
+		}
+		s.committed(rev, p)
+		return
+	}
+
+	if id == "" {
+		s.forwarded++
+		id = fmt.Sprintf("%v-%v", s.token, s.forwarded)
+	}
+	s.pending[ot.RevisionID{UserID: p.userID, ID: id}] = p
+	s.forward(id, p)
+}
+
+// takePending removes the operation the user sent with id from the pending
+// ones and returns it, or nil if it isn't pending.
+func (s *session) takePending(userID, id string) *pendingOp {
+	key := ot.RevisionID{UserID: userID, ID: id}
+	p := s.pending[key]
+	delete(s.pending, key)
+	return p
+}
+
+func (s *session) forward(id string, p *pendingOp) {
+	s.publish(&clusterMessage{
+		Kind:       kindSubmit,
+		UserID:     p.userID,
+		Connection: p.connectionID,
+		ID:         id,
+		Version:    p.version,
+		Unit:       p.unit,
+		Ops:        p.ops,
+		Reverts:    p.reverts,
+	})
+}
+
+// committed lets everyone in the session know about rev. p is the operation
+// rev was made from, if it came from this replica.
+func (s *session) committed(rev *ot.Revision, p *pendingOp) {
+	s.moveCursors(rev)
+	s.record(rev)
+	var except *client
+	if p != nil {
+		except = p.client
+		p.done(rev, nil)
+	}
+	s.broadcast(rev, except)
+}
+
+func (s *session) handleCluster(m *clusterMessage) {
+	if m.Instance == s.token || s.history == nil {
+		return
+	}
+
+	switch m.Kind {
+	case kindSubmit:
+		if s.leader {
+			s.handleSubmit(m)
+		}
+	case kindRevision:
+		if !s.leader {
+			s.handleRevision(m.Revision)
+		}
+	case kindError:
+		if p := s.takePending(m.UserID, m.ID); p != nil {
+			p.done(nil, &remoteError{
+				code:    m.Error.Code,
+				message: m.Error.Message,
+				resync:  m.Error.Resync,
+			})
+		}
+	case kindLeader:
+		for key, p := range s.pending {
+			s.forward(key.ID, p)
+		}
+	case kindResign:
+		s.renew()
+	case kindPresence:
+		s.handlePresence(m)
+	case kindCursor:
+		s.handleRemoteCursor(m)
+	case kindCrdt:
+		if s.crdt != nil {
+			s.handleRemoteCrdt(m.Crdt)
+		}
+	case kindDeleted:
+		s.closeDeleted()
+	case kindSkipped:
+		if p := s.takePending(m.UserID, m.ID); p != nil {
+			p.done(nil, nil)
+		}
+	}
+}
+
+// handleSubmit sequences operations forwarded by another replica.
+func (s *session) handleSubmit(m *clusterMessage) {
+	if rev := s.history.Find(m.UserID, m.ID); rev != nil {
+		// publishing it again lets the replica waiting for it answer
+		s.publish(&clusterMessage{Kind: kindRevision, Revision: rev})
+		return
+	}
+
+	s.sequence(m.ID, &pendingOp{
+		userID:       m.UserID,
+		connectionID: m.Connection,
+		ops:          m.Ops,
+		version:      m.Version,
+		unit:         m.Unit,
+		reverts:      m.Reverts,
+		done: func(rev *ot.Revision, err error) {
+			if err == nil {
+				if rev == nil {
+					s.publish(&clusterMessage{Kind: kindSkipped, UserID: m.UserID, ID: m.ID})
+				}
+				return
+			}
+			code, resync := errorCode(err)
+			s.publish(&clusterMessage{
+				Kind:   kindError,
+				UserID: m.UserID,
+				ID:     m.ID,
+				Error: &api_pb.Error{
+					Code:    code,
+					Message: err.Error(),
+					Resync:  resync,
+				},
+			})
+		},
+	})
+}
+
+// handleRevision follows a revision applied by the sequencer.
+func (s *session) handleRevision(rev *ot.Revision) {
+	switch version := s.history.Version(); {
+	case rev.Version <= version:
+		if p := s.takePending(rev.UserID, rev.ID); p != nil && rev.ID != "" {
+			p.done(rev, nil)
+		}
+		return
+	case rev.Version > version+1:
+		// some revisions were missed, but the log has all of them
+		s.reload()
+		return
+	}
+
+	if _, err := s.text.Apply(rev.Ops, api_pb.PositionUnit_CODE_POINTS); err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Int32("version", rev.Version).Msg("error following revision")
+		s.reload()
+		return
+	}
+	if err := s.history.Append(rev); err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Int32("version", rev.Version).Msg("error following revision")
+		s.reload()
+		return
+	}
+	if snapshotInterval > 0 && rev.Version%snapshotInterval == 0 {
+		s.history.Compact(retained(s.history) - 1)
+	}
+
+	s.committed(rev, s.takePending(rev.UserID, rev.ID))
+}
+
+// reload reads the document from Redis again and catches the clients up with
+// whatever changed since.
+func (s *session) reload() {
+	if s.doc.Mode == modeCRDT {
+		s.reloadCRDT()
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	text, history, err := loadDocument(ctx, s.doc.ID)
+	cancel()
+	if err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error reloading document")
+		return
+	}
+
+	if s.history == nil {
+		s.text, s.history = text, history
+		return
+	}
+	version := s.history.Version()
+	s.text, s.history = text, history
+	revs, err := history.Since(version)
+	if err != nil {
+		for cl := range s.clients {
+			s.resync(cl)
+		}
+		return
+	}
+	for _, rev := range revs {
+		s.committed(rev, s.takePending(rev.UserID, rev.ID))
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// Clients pick how events are encoded with the Sec-WebSocket-Protocol header.
+// The v1 protocols wrap events in Event, the v2 ones in Envelope. Clients that
+// don't ask for a subprotocol get cloudocs.json.v1.
+const (
+	protocolProtoV2 = "cloudocs.proto.v2"
+	protocolJSONV2  = "cloudocs.json.v2"
+	protocolProto   = "cloudocs.proto.v1"
+	protocolJSON    = "cloudocs.json.v1"
+)
+
+// protocols lists the subprotocols in order of preference.
+var protocols = []string{protocolProtoV2, protocolJSONV2, protocolProto, protocolJSON}
+
+// codec encodes the events of a connection.
+type codec interface {
+	// encode encodes an event answering the request with the given id, if any.
+	encode(requestID string, t api_pb.Event_EventType, msg proto.Message) ([]byte, error)
+	// decode decodes an event sent by a client into its payload.
+	decode(data []byte) (requestID string, msg proto.Message, err error)
+	// messageType is the websocket message type events are sent in.
+	messageType() int
+	// hello reports whether clients start with a Hello.
+	hello() bool
+}
+
+func codecFor(protocol string) codec {
+	switch protocol {
+	case protocolProtoV2:
+		return envelopeCodec{protoFormat{}}
+	case protocolJSONV2:
+		return envelopeCodec{jsonFormat{}}
+	case protocolProto:
+		return eventCodec{protoFormat{}}
+	default:
+		return eventCodec{jsonFormat{}}
+	}
+}
+
+// format encodes single messages.
+type format interface {
+	marshal(msg proto.Message) ([]byte, error)
+	unmarshal(data []byte, msg proto.Message) error
+	messageType() int
+}
+
+type jsonFormat struct{}
+
+func (jsonFormat) marshal(msg proto.Message) ([]byte, error) {
+	s, err := encoder.MarshalToString(msg)
+	return []byte(s), err
+}
+
+func (jsonFormat) unmarshal(data []byte, msg proto.Message) error {
+	return decoder.Unmarshal(bytes.NewReader(data), msg)
+}
+
+func (jsonFormat) messageType() int {
+	return websocket.TextMessage
+}
+
+type protoFormat struct{}
+
+func (protoFormat) marshal(msg proto.Message) ([]byte, error) {
+	return proto.Marshal(msg)
+}
+
+func (protoFormat) unmarshal(data []byte, msg proto.Message) error {
+	return proto.Unmarshal(data, msg)
+}
+
+func (protoFormat) messageType() int {
+	return websocket.BinaryMessage
+}
+
+// eventCodec wraps events in Event, with the payload encoded separately into
+// its event field. Request ids are not supported.
+type eventCodec struct {
+	format
+}
+
+func (c eventCodec) encode(_ string, t api_pb.Event_EventType, msg proto.Message) ([]byte, error) {
+	payload, err := c.marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return c.marshal(&api_pb.Event{
+		Type:  t,
+		Event: payload,
+	})
+}
+
+func (c eventCodec) decode(data []byte) (string, proto.Message, error) {
+	var ev api_pb.Event
+	if err := c.unmarshal(data, &ev); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", errMalformedEvent, err)
+	}
+	msg, err := c.payload(&ev)
+	return "", msg, err
+}
+
+// payload decodes the payload of ev.
+func (c eventCodec) payload(ev *api_pb.Event) (proto.Message, error) {
+	var msg proto.Message
+	switch ev.Type {
+	case api_pb.Event_OPERATION:
+		msg = &api_pb.Operation{}
+	case api_pb.Event_OPERATION_BATCH:
+		msg = &api_pb.OperationBatch{}
+	case api_pb.Event_CURSOR:
+		msg = &api_pb.Cursor{}
+	case api_pb.Event_CHECKSUM:
+		msg = &api_pb.Checksum{}
+	case api_pb.Event_CRDT_SYNC:
+		msg = &api_pb.CrdtSync{}
+	case api_pb.Event_CRDT_UPDATE:
+		msg = &api_pb.CrdtUpdate{}
+	case api_pb.Event_UNDO:
+		msg = &api_pb.Undo{}
+	case api_pb.Event_REDO:
+		msg = &api_pb.Redo{}
+	default:
+		return nil, fmt.Errorf("%w: %v", errUnsupportedEvent, ev.Type)
+	}
+	// Undo and Redo have no fields, clients may leave out their payload
+	if len(ev.Event) == 0 && (ev.Type == api_pb.Event_UNDO || ev.Type == api_pb.Event_REDO) {
+		return msg, nil
+	}
+	if err := c.unmarshal(ev.Event, msg); err != nil {
+		return nil, fmt.Errorf("%w: %v", errMalformedEvent, err)
+	}
+	return msg, nil
+}
+
+func (eventCodec) hello() bool {
+	return false
+}
+
+// envelopeCodec wraps events in Envelope, which holds the payload directly.
+type envelopeCodec struct {
+	format
+}
+
+func (c envelopeCodec) encode(requestID string, t api_pb.Event_EventType, msg proto.Message) ([]byte, error) {
+	env := &api_pb.Envelope{RequestId: requestID}
+	switch t {
+	case api_pb.Event_INIT:
+		env.Event = &api_pb.Envelope_Init{Init: msg.(*api_pb.Init)}
+	case api_pb.Event_CLIENT_JOINED:
+		env.Event = &api_pb.Envelope_ClientJoined{ClientJoined: msg.(*api_pb.Participant)}
+	case api_pb.Event_CLIENT_QUIT:
+		env.Event = &api_pb.Envelope_ClientQuit{ClientQuit: msg.(*api_pb.Participant)}
+	case api_pb.Event_OPERATION:
+		env.Event = &api_pb.Envelope_Operation{Operation: msg.(*api_pb.Operation)}
+	case api_pb.Event_OPERATION_ACK:
+		env.Event = &api_pb.Envelope_OperationAck{OperationAck: msg.(*api_pb.OperationAck)}
+	case api_pb.Event_CURSOR:
+		env.Event = &api_pb.Envelope_Cursor{Cursor: msg.(*api_pb.Cursor)}
+	case api_pb.Event_RESUME:
+		env.Event = &api_pb.Envelope_Resume{Resume: msg.(*api_pb.Resume)}
+	case api_pb.Event_ERROR:
+		env.Event = &api_pb.Envelope_Error{Error: msg.(*api_pb.Error)}
+	case api_pb.Event_CHECKSUM:
+		env.Event = &api_pb.Envelope_Checksum{Checksum: msg.(*api_pb.Checksum)}
+	case api_pb.Event_RESYNC:
+		env.Event = &api_pb.Envelope_Resync{Resync: msg.(*api_pb.Resync)}
+	case api_pb.Event_OPERATION_BATCH:
+		env.Event = &api_pb.Envelope_OperationBatch{OperationBatch: msg.(*api_pb.OperationBatch)}
+	case api_pb.Event_CRDT_SYNC:
+		env.Event = &api_pb.Envelope_CrdtSync{CrdtSync: msg.(*api_pb.CrdtSync)}
+	case api_pb.Event_CRDT_UPDATE:
+		env.Event = &api_pb.Envelope_CrdtUpdate{CrdtUpdate: msg.(*api_pb.CrdtUpdate)}
+	default:
+		return nil, fmt.Errorf("%w: %v", errUnsupportedEvent, t)
+	}
+	return c.marshal(env)
+}
+
+func (c envelopeCodec) decode(data []byte) (string, proto.Message, error) {
+	var env api_pb.Envelope
+	if err := c.unmarshal(data, &env); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", errMalformedEvent, err)
+	}
+
+	switch ev := env.Event.(type) {
+	case *api_pb.Envelope_Operation:
+		return env.RequestId, ev.Operation, nil
+	case *api_pb.Envelope_OperationBatch:
+		return env.RequestId, ev.OperationBatch, nil
+	case *api_pb.Envelope_Cursor:
+		return env.RequestId, ev.Cursor, nil
+	case *api_pb.Envelope_Checksum:
+		return env.RequestId, ev.Checksum, nil
+	case *api_pb.Envelope_Hello:
+		return env.RequestId, ev.Hello, nil
+	case *api_pb.Envelope_CrdtSync:
+		return env.RequestId, ev.CrdtSync, nil
+	case *api_pb.Envelope_CrdtUpdate:
+		return env.RequestId, ev.CrdtUpdate, nil
+	case *api_pb.Envelope_Undo:
+		return env.RequestId, ev.Undo, nil
+	case *api_pb.Envelope_Redo:
+		return env.RequestId, ev.Redo, nil
+	default:
+		return env.RequestId, nil, fmt.Errorf("%w: %T", errUnsupportedEvent, env.Event)
+	}
+}
+
+func (envelopeCodec) hello() bool {
+	return true
+}
@@ -0,0 +1,36 @@
+package util
+
+import (
+	"github.com/rs/zerolog/log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GetEnvInt returns the integer value of the environment variable key, or def
+// if it is not set.
+func GetEnvInt(key string, def int) int {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatal().Err(err).Str("key", key).Msg("invalid environment variable")
+	}
+	return n
+}
+
+// GetEnvDuration returns the duration value of the environment variable key,
+// or def if it is not set.
+func GetEnvDuration(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatal().Err(err).Str("key", key).Msg("invalid environment variable")
+	}
+	return d
+}
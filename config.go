@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/ssau-fiit/cloudocs-api/common/util"
+	"time"
+)
+
+var (
+	// a snapshot of the document text is stored every snapshotInterval versions
+	snapshotInterval = int32(util.GetEnvInt("SNAPSHOT_INTERVAL", 100))
+	// operations older than opsRetention are dropped from the log once a newer
+	// snapshot exists
+	opsRetention = util.GetEnvDuration("OPS_RETENTION", 7*24*time.Hour)
+	// texts of open documents are written back to texts.<id> every
+	// flushInterval if they changed
+	flushInterval = util.GetEnvDuration("FLUSH_INTERVAL", time.Second)
+	// the sequencer of a document has to renew its lease within leaseTTL, or
+	// another replica takes over
+	leaseTTL = util.GetEnvDuration("LEASE_TTL", 10*time.Second)
+	// clients are pinged every pingInterval and disconnected if no pong or
+	// other message arrives within pongTimeout
+	pingInterval = util.GetEnvDuration("PING_INTERVAL", 30*time.Second)
+	pongTimeout  = util.GetEnvDuration("PONG_TIMEOUT", 60*time.Second)
+	// clients of the v2 subprotocols have to send Hello within helloTimeout
+	helloTimeout = util.GetEnvDuration("HELLO_TIMEOUT", 10*time.Second)
+	// a write to a client taking longer than writeTimeout disconnects it
+	writeTimeout = util.GetEnvDuration("WRITE_TIMEOUT", 10*time.Second)
+	// the gRPC CollabService listens on grpcPort
+	grpcPort = util.GetEnvInt("GRPC_PORT", 8081)
+	// clients with more than sendQueueSize events waiting to be written are
+	// disconnected
+	sendQueueSize = util.GetEnvInt("SEND_QUEUE_SIZE", 256)
+	// every user can undo up to undoDepth of its latest revisions
+	undoDepth = util.GetEnvInt("UNDO_DEPTH", 100)
+)
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/rs/zerolog/log"
+	"github.com/ssau-fiit/cloudocs-api/crdt"
+	"github.com/ssau-fiit/cloudocs-api/database"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"time"
+)
+
+// Documents in CRDT mode are edited through a sequence CRDT instead of OT.
+// Clients make their own ops, which need no sequencer: every replica applies
+// them as they come, stores them in the crdt.<id> stream and relays them to
+// its clients and the other replicas. The text in texts.<id> is still kept up
+// to date by the replica holding the lease.
+
+// crdtSite is the site of the ops the server makes itself.
+const crdtSite = "cloudocs"
+
+func crdtKey(docID string) string {
+	return fmt.Sprintf("crdt.%v", docID)
+}
+
+// saveCRDT appends ops to the CRDT log of the document. It returns
+// errDocumentDeleted if the document no longer exists.
+func saveCRDT(ctx context.Context, docID string, ops []*api_pb.CrdtOp) error {
+	data, err := proto.Marshal(&api_pb.CrdtUpdate{Ops: ops})
+	if err != nil {
+		return err
+	}
+	return appendEntry(ctx, docID, crdtKey(docID), "*", "update", data)
+}
+
+// seedCRDT starts the CRDT log of a new document with its placeholder text.
+func seedCRDT(ctx context.Context, docID, text string) error {
+	return saveCRDT(ctx, docID, []*api_pb.CrdtOp{{
+		Id: &api_pb.CrdtId{Site: crdtSite, Clock: 1},
+		Op: &api_pb.CrdtOp_Insert{Insert: &api_pb.CrdtInsert{Text: text}},
+	}})
+}
+
+// loadCRDT replays the CRDT log of the document.
+func loadCRDT(ctx context.Context, docID string) (*crdt.Doc, error) {
+	msgs, err := database.Database().XRange(ctx, crdtKey(docID), "-", "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	doc := crdt.New()
+	for _, msg := range msgs {
+		data, _ := msg.Values["update"].(string)
+		var update api_pb.CrdtUpdate
+		if err := proto.Unmarshal([]byte(data), &update); err != nil {
+			return nil, fmt.Errorf("update %v: %w", msg.ID, err)
+		}
+		if _, _, err := doc.Apply(update.Ops); err != nil {
+			return nil, fmt.Errorf("update %v: %w", msg.ID, err)
+		}
+	}
+	return doc, nil
+}
+
+// handleCrdtUpdate applies the ops a client made, and answers with the state
+// vector of the server once they are stored.
+func (s *session) handleCrdtUpdate(cl *client, requestID string, update *api_pb.CrdtUpdate) {
+	applied, err := s.merge(update.Ops)
+	if len(applied) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		saveErr := saveCRDT(ctx, s.doc.ID, applied)
+		cancel()
+		if errors.Is(saveErr, errDocumentDeleted) {
+			s.closeDeleted()
+			return
+		}
+		if saveErr != nil {
+			log.Error().Err(saveErr).Str("document", s.doc.ID).Msg("error saving crdt update")
+			cl.fail(requestID, saveErr, nil)
+			// the ops are only kept if they made it to the log
+			s.reload()
+			return
+		}
+
+		relayed := &api_pb.CrdtUpdate{Ops: applied}
+		if data, err := proto.Marshal(relayed); err == nil {
+			s.publish(&clusterMessage{Kind: kindCrdt, Crdt: data})
+		}
+		s.notify(api_pb.Event_CRDT_UPDATE, relayed, cl)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error applying crdt update")
+		cl.fail(requestID, err, nil)
+		return
+	}
+
+	cl.reply(requestID, api_pb.Event_CRDT_SYNC, &api_pb.CrdtSync{StateVector: s.crdt.State()})
+}
+
+// handleCrdtSync sends a client every op it has not seen yet along with the
+// state vector of the server, so it can send back the ops the server lacks.
+func (s *session) handleCrdtSync(cl *client, requestID string, sync *api_pb.CrdtSync) {
+	cl.reply(requestID, api_pb.Event_CRDT_SYNC, &api_pb.CrdtSync{
+		StateVector: s.crdt.State(),
+		Ops:         s.crdt.Since(sync.StateVector),
+	})
+}
+
+// handleRemoteCrdt follows ops applied by another replica.
+func (s *session) handleRemoteCrdt(data []byte) {
+	var update api_pb.CrdtUpdate
+	err := proto.Unmarshal(data, &update)
+	var applied []*api_pb.CrdtOp
+	if err == nil {
+		applied, err = s.merge(update.Ops)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error following crdt update")
+		s.reload()
+		return
+	}
+	if len(applied) > 0 {
+		s.notify(api_pb.Event_CRDT_UPDATE, &api_pb.CrdtUpdate{Ops: applied}, nil)
+	}
+}
+
+// merge applies ops to the document and its text, and returns the ones that
+// were new.
+func (s *session) merge(ops []*api_pb.CrdtOp) ([]*api_pb.CrdtOp, error) {
+	applied, changes, err := s.crdt.Apply(ops)
+	if len(changes) > 0 {
+		if _, err := s.text.Apply(changes, api_pb.PositionUnit_CODE_POINTS); err != nil {
+			// the text is derived from the document, so it can't really
+			// disagree with it
+			s.text = ot.NewBuffer(s.crdt.String())
+		}
+		if s.leader {
+			s.markDirty()
+		}
+	}
+	return applied, err
+}
+
+// reloadCRDT reads the document from its log again and brings the clients up
+// to date.
+func (s *session) reloadCRDT() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	doc, err := loadCRDT(ctx, s.doc.ID)
+	cancel()
+	if err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error reloading document")
+		return
+	}
+
+	var missed []*api_pb.CrdtOp
+	if s.crdt != nil {
+		missed = doc.Since(s.crdt.State())
+	}
+	s.crdt = doc
+	s.text = ot.NewBuffer(doc.String())
+	if s.history == nil {
+		s.history = ot.NewHistory(0)
+	}
+	if s.leader {
+		s.markDirty()
+	}
+	if len(missed) > 0 {
+		s.notify(api_pb.Event_CRDT_UPDATE, &api_pb.CrdtUpdate{Ops: missed}, nil)
+	}
+}
@@ -0,0 +1,239 @@
+package crdt
+
+import (
+	"errors"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalid = errors.New("crdt: invalid op")
+	ErrMissing = errors.New("crdt: op depends on unknown characters")
+)
+
+// id identifies a character or a deletion. The zero id stands for the start of
+// the document.
+type id struct {
+	site  string
+	clock uint64
+}
+
+func idOf(p *api_pb.CrdtId) id {
+	if p == nil {
+		return id{}
+	}
+	return id{p.Site, p.Clock}
+}
+
+// precedes reports whether a goes before b among characters inserted right
+// after the same origin. Later inserts come first, ties are broken by site.
+func precedes(a, b id) bool {
+	return a.clock > b.clock || a.clock == b.clock && a.site > b.site
+}
+
+type item struct {
+	id      id
+	char    rune
+	deleted bool
+	next    *item
+}
+
+// Doc is a text edited by several sites at once: a sequence CRDT in the style
+// of RGA. Every character ever inserted keeps its place in a linked list and
+// deleted ones stay as tombstones, so ops from different sites can be applied
+// in any order that respects their dependencies and every site ends up with
+// the same text.
+//
+// The ops of every site have to arrive in the order of their clocks. An op
+// with a clock no higher than the latest one seen from its site is taken for
+// one already applied.
+type Doc struct {
+	head  item
+	items map[id]*item
+	// state is the highest clock seen from every site
+	state map[string]uint64
+	// ops are all the ops applied, in order
+	ops []*api_pb.CrdtOp
+}
+
+// New returns an empty document.
+func New() *Doc {
+	return &Doc{
+		items: make(map[id]*item),
+		state: make(map[string]uint64),
+	}
+}
+
+// String returns the text of the document.
+func (d *Doc) String() string {
+	var sb strings.Builder
+	for it := d.head.next; it != nil; it = it.next {
+		if !it.deleted {
+			sb.WriteRune(it.char)
+		}
+	}
+	return sb.String()
+}
+
+// State returns the highest clock seen from every site.
+func (d *Doc) State() map[string]uint64 {
+	res := make(map[string]uint64, len(d.state))
+	for site, clock := range d.state {
+		res[site] = clock
+	}
+	return res
+}
+
+// Since returns the ops that a site with the given state has not seen, in the
+// order they were applied.
+func (d *Doc) Since(state map[string]uint64) []*api_pb.CrdtOp {
+	var res []*api_pb.CrdtOp
+	for _, op := range d.ops {
+		if last(op) > state[op.Id.Site] {
+			res = append(res, op)
+		}
+	}
+	return res
+}
+
+// Apply applies ops in order, skipping the ones already applied, and stops at
+// the first one that fails. It returns the ops that were new along with the
+// changes they made to the text, as operations with positions counted in code
+// points.
+func (d *Doc) Apply(ops []*api_pb.CrdtOp) ([]*api_pb.CrdtOp, []*api_pb.Operation, error) {
+	var applied []*api_pb.CrdtOp
+	var changes []*api_pb.Operation
+	for _, op := range ops {
+		if op.Id == nil || op.Id.Site == "" || op.Id.Clock == 0 {
+			return applied, changes, ErrInvalid
+		}
+		if op.Id.Clock <= d.state[op.Id.Site] {
+			continue
+		}
+
+		var change []*api_pb.Operation
+		var err error
+		switch o := op.Op.(type) {
+		case *api_pb.CrdtOp_Insert:
+			change, err = d.insert(idOf(op.Id), o.Insert)
+		case *api_pb.CrdtOp_Delete:
+			change, err = d.delete(o.Delete)
+		default:
+			err = ErrInvalid
+		}
+		if err != nil {
+			return applied, changes, err
+		}
+
+		d.state[op.Id.Site] = last(op)
+		d.ops = append(d.ops, op)
+		applied = append(applied, op)
+		changes = append(changes, change...)
+	}
+	return applied, changes, nil
+}
+
+func (d *Doc) insert(opID id, ins *api_pb.CrdtInsert) ([]*api_pb.Operation, error) {
+	if ins == nil || ins.Text == "" || !utf8.ValidString(ins.Text) {
+		return nil, ErrInvalid
+	}
+	origin := idOf(ins.Origin)
+	prev := &d.head
+	if origin != (id{}) {
+		var ok bool
+		if prev, ok = d.items[origin]; !ok {
+			return nil, ErrMissing
+		}
+	}
+	// the site had seen origin, so its clock must be past it
+	if opID.clock <= origin.clock {
+		return nil, ErrInvalid
+	}
+
+	for prev.next != nil && precedes(prev.next.id, opID) {
+		prev = prev.next
+	}
+	index := d.index(prev)
+
+	change := &api_pb.Operation{
+		Type:  api_pb.OpType_INSERT,
+		Index: index,
+		Text:  ins.Text,
+	}
+	for _, r := range ins.Text {
+		it := &item{id: opID, char: r, next: prev.next}
+		prev.next = it
+		d.items[opID] = it
+		prev = it
+		opID.clock++
+		change.Len++
+	}
+	return []*api_pb.Operation{change}, nil
+}
+
+func (d *Doc) delete(del *api_pb.CrdtDelete) ([]*api_pb.Operation, error) {
+	if del == nil || del.Len == 0 {
+		return nil, ErrInvalid
+	}
+	target := idOf(del.Target)
+	targets := make(map[*item]bool, del.Len)
+	for i := uint64(0); i < uint64(del.Len); i++ {
+		it, ok := d.items[id{target.site, target.clock + i}]
+		if !ok {
+			return nil, ErrMissing
+		}
+		if !it.deleted {
+			targets[it] = true
+		}
+	}
+
+	var changes []*api_pb.Operation
+	var index int32
+	for it := d.head.next; it != nil && len(targets) > 0; it = it.next {
+		if it.deleted {
+			continue
+		}
+		if !targets[it] {
+			index++
+			continue
+		}
+		delete(targets, it)
+		it.deleted = true
+		if n := len(changes); n > 0 && changes[n-1].Index == index {
+			changes[n-1].Len++
+			continue
+		}
+		changes = append(changes, &api_pb.Operation{
+			Type:  api_pb.OpType_DELETE,
+			Index: index,
+			Len:   1,
+		})
+	}
+	return changes, nil
+}
+
+// index returns the number of characters up to and including it.
+func (d *Doc) index(it *item) int32 {
+	if it == &d.head {
+		return 0
+	}
+	var n int32
+	for cur := d.head.next; cur != nil; cur = cur.next {
+		if !cur.deleted {
+			n++
+		}
+		if cur == it {
+			break
+		}
+	}
+	return n
+}
+
+// last returns the highest clock taken by op.
+func last(op *api_pb.CrdtOp) uint64 {
+	if ins := op.GetInsert(); ins != nil {
+		return op.Id.Clock + uint64(utf8.RuneCountInString(ins.Text)) - 1
+	}
+	return op.Id.Clock
+}
@@ -0,0 +1,237 @@
+package crdt
+
+import (
+	"errors"
+	"fmt"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"math/rand"
+	"testing"
+	"unicode/utf8"
+)
+
+func insert(site string, clock uint64, origin *api_pb.CrdtId, text string) *api_pb.CrdtOp {
+	return &api_pb.CrdtOp{
+		Id: &api_pb.CrdtId{Site: site, Clock: clock},
+		Op: &api_pb.CrdtOp_Insert{Insert: &api_pb.CrdtInsert{Origin: origin, Text: text}},
+	}
+}
+
+func remove(site string, clock uint64, target *api_pb.CrdtId, n uint32) *api_pb.CrdtOp {
+	return &api_pb.CrdtOp{
+		Id: &api_pb.CrdtId{Site: site, Clock: clock},
+		Op: &api_pb.CrdtOp_Delete{Delete: &api_pb.CrdtDelete{Target: target, Len: n}},
+	}
+}
+
+func at(site string, clock uint64) *api_pb.CrdtId {
+	return &api_pb.CrdtId{Site: site, Clock: clock}
+}
+
+// apply applies ops to d and checks the changes it reports turn the text from
+// before into the text after.
+func apply(t *testing.T, d *Doc, ops ...*api_pb.CrdtOp) []*api_pb.CrdtOp {
+	t.Helper()
+	text := []rune(d.String())
+	applied, changes, err := d.Apply(ops)
+	if err != nil {
+		t.Fatalf("applying %v: %v", ops, err)
+	}
+	for _, c := range changes {
+		if c.Type == api_pb.OpType_INSERT {
+			text = append(text[:c.Index:c.Index], append([]rune(c.Text), text[c.Index:]...)...)
+		} else {
+			text = append(text[:c.Index:c.Index], text[c.Index+c.Len:]...)
+		}
+	}
+	if string(text) != d.String() {
+		t.Fatalf("changes %v give %q, the document holds %q", changes, string(text), d.String())
+	}
+	return applied
+}
+
+func TestConcurrentInserts(t *testing.T) {
+	// every op comes from another site, so any order respects their clocks
+	base := insert("a", 1, nil, "xy")
+	ops := []*api_pb.CrdtOp{
+		insert("a", 3, at("a", 1), "A"),
+		insert("b", 3, at("a", 1), "B"),
+		insert("c", 4, at("a", 1), "C"),
+		remove("d", 4, at("a", 2), 1),
+		remove("e", 5, at("a", 2), 1),
+	}
+
+	var want string
+	for i := 0; i < 20; i++ {
+		d := New()
+		apply(t, d, base)
+		for _, j := range rand.New(rand.NewSource(int64(i))).Perm(len(ops)) {
+			apply(t, d, ops[j])
+		}
+		if i == 0 {
+			want = d.String()
+		}
+		if d.String() != want {
+			t.Fatalf("got %q, want %q", d.String(), want)
+		}
+	}
+	// later inserts go first, ties are broken by site
+	if want != "xCBA" {
+		t.Errorf("got %q, want %q", want, "xCBA")
+	}
+}
+
+// site is a replica of a document making its own random edits.
+type site struct {
+	name  string
+	doc   *Doc
+	clock uint64
+}
+
+// tick returns the clock of the next op, past everything the site has seen.
+func (s *site) tick(n int) uint64 {
+	for _, c := range s.doc.state {
+		if c > s.clock {
+			s.clock = c
+		}
+	}
+	clock := s.clock + 1
+	s.clock += uint64(n)
+	return clock
+}
+
+func (s *site) edit(r *rand.Rand) *api_pb.CrdtOp {
+	var visible []*item
+	for it := s.doc.head.next; it != nil; it = it.next {
+		if !it.deleted {
+			visible = append(visible, it)
+		}
+	}
+
+	if len(visible) > 0 && r.Intn(3) == 0 {
+		i := r.Intn(len(visible))
+		target := visible[i].id
+		// extend the deletion over the following characters of the same insert
+		n := 1
+		for i+n < len(visible) && visible[i+n].id == (id{target.site, target.clock + uint64(n)}) && r.Intn(2) == 0 {
+			n++
+		}
+		return remove(s.name, s.tick(1), at(target.site, target.clock), uint32(n))
+	}
+
+	var origin *api_pb.CrdtId
+	if p := r.Intn(len(visible) + 1); p > 0 {
+		origin = at(visible[p-1].id.site, visible[p-1].id.clock)
+	}
+	text := []string{"a", "bc", "é", "😀x"}[r.Intn(4)]
+	return insert(s.name, s.tick(utf8.RuneCountInString(text)), origin, text)
+}
+
+func TestConcurrentSitesConverge(t *testing.T) {
+	for seed := 0; seed < 100; seed++ {
+		r := rand.New(rand.NewSource(int64(seed)))
+		sites := make([]*site, 4)
+		for i := range sites {
+			sites[i] = &site{name: fmt.Sprint("site", i), doc: New()}
+		}
+
+		for round := 0; round < 20; round++ {
+			for _, s := range sites {
+				for i := r.Intn(4); i > 0; i-- {
+					apply(t, s.doc, s.edit(r))
+				}
+			}
+			// every site catches up with a random other one, so ops reach
+			// the sites in different orders
+			for _, s := range sites {
+				other := sites[r.Intn(len(sites))]
+				apply(t, s.doc, other.doc.Since(s.doc.State())...)
+			}
+		}
+
+		for _, s := range sites {
+			for _, other := range sites {
+				apply(t, s.doc, other.doc.Since(s.doc.State())...)
+			}
+		}
+		for _, s := range sites[1:] {
+			if s.doc.String() != sites[0].doc.String() {
+				t.Fatalf("seed %v: %v has %q, %v has %q", seed, s.name, s.doc.String(), sites[0].name, sites[0].doc.String())
+			}
+		}
+	}
+}
+
+func TestMissing(t *testing.T) {
+	d := New()
+	apply(t, d, insert("a", 1, nil, "ab"))
+
+	for _, op := range []*api_pb.CrdtOp{
+		insert("b", 5, at("c", 4), "x"),
+		remove("b", 5, at("c", 4), 1),
+		// reaches past the end of the insert
+		remove("b", 5, at("a", 2), 2),
+	} {
+		applied, changes, err := d.Apply([]*api_pb.CrdtOp{op})
+		if !errors.Is(err, ErrMissing) {
+			t.Errorf("applying %v: got %v, want ErrMissing", op, err)
+		}
+		if len(applied) != 0 || len(changes) != 0 || d.String() != "ab" || d.State()["b"] != 0 {
+			t.Errorf("applying %v changed the document", op)
+		}
+	}
+
+	// ops before the failing one stay applied
+	applied, _, err := d.Apply([]*api_pb.CrdtOp{insert("b", 3, at("a", 2), "c"), insert("b", 5, at("c", 4), "x")})
+	if !errors.Is(err, ErrMissing) || len(applied) != 1 || d.String() != "abc" {
+		t.Errorf("got %v ops applied, %q, %v", len(applied), d.String(), err)
+	}
+}
+
+func TestAlreadySeen(t *testing.T) {
+	d := New()
+	ops := []*api_pb.CrdtOp{
+		insert("a", 1, nil, "abc"),
+		remove("a", 4, at("a", 2), 1),
+	}
+	apply(t, d, ops...)
+
+	applied := apply(t, d, ops...)
+	if len(applied) != 0 || d.String() != "ac" {
+		t.Errorf("applying again gave %v ops and %q", len(applied), d.String())
+	}
+	// a clock the site already used counts as seen, whatever the op
+	applied = apply(t, d, insert("a", 3, at("a", 3), "x"))
+	if len(applied) != 0 || d.String() != "ac" {
+		t.Errorf("old clock gave %v ops and %q", len(applied), d.String())
+	}
+	if since := d.Since(d.State()); len(since) != 0 {
+		t.Errorf("got %v ops since the current state", len(since))
+	}
+	if since := d.Since(map[string]uint64{"a": 3}); len(since) != 1 || since[0] != ops[1] {
+		t.Errorf("got %v since the insert", since)
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	d := New()
+	apply(t, d, insert("a", 1, nil, "ab"))
+
+	for _, op := range []*api_pb.CrdtOp{
+		{Op: &api_pb.CrdtOp_Insert{Insert: &api_pb.CrdtInsert{Text: "x"}}},
+		insert("", 3, nil, "x"),
+		insert("b", 0, nil, "x"),
+		insert("b", 3, nil, ""),
+		insert("b", 3, nil, "\xff"),
+		// an insert has to come after its origin
+		insert("b", 2, at("a", 2), "x"),
+		remove("b", 3, at("a", 1), 0),
+		{Id: at("b", 3)},
+	} {
+		if _, _, err := d.Apply([]*api_pb.CrdtOp{op}); !errors.Is(err, ErrInvalid) {
+			t.Errorf("applying %v: got %v, want ErrInvalid", op, err)
+		}
+	}
+	if d.String() != "ab" {
+		t.Errorf("invalid ops changed the text to %q", d.String())
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// handleCursor stores the client's cursor at the latest version and relays it
+// to everyone else in the document.
+func (s *session) handleCursor(cl *client, requestID string, cursor *api_pb.Cursor) {
+	var pos [2]int32
+	for i, p := range []int32{cursor.Anchor, cursor.Head} {
+		p, err := s.history.TransformPosition(p, cursor.Version, cl.unit, cl.connID)
+		if err == nil {
+			p, err = s.text.ToCodePoints(p, cl.unit)
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("invalid cursor")
+			s.fail(cl, requestID, err, nil)
+			return
+		}
+		pos[i] = p
+	}
+
+	cl.cursor = &api_pb.Cursor{
+		UserId:       cl.id,
+		Anchor:       pos[0],
+		Head:         pos[1],
+		Version:      s.history.Version(),
+		ConnectionId: cl.connID,
+	}
+	for other := range s.clients {
+		if other != cl && other.can(api_pb.Capability_CURSORS) {
+			other.send(api_pb.Event_CURSOR, cursorIn(s.text, cl.cursor, other.unit))
+		}
+	}
+	s.publish(&clusterMessage{Kind: kindCursor, Cursor: cl.cursor})
+}
+
+// handleRemoteCursor stores the cursor of a client connected to another
+// replica and relays it to the local clients.
+func (s *session) handleRemoteCursor(m *clusterMessage) {
+	r, ok := s.remote[m.Instance]
+	if !ok {
+		return
+	}
+	cursor := m.Cursor
+	// the other replica may not have seen the latest revisions yet; cursors
+	// from ahead are moved by the revisions still to come
+	if cursor.Version < s.history.Version() {
+		var pos [2]int32
+		for i, p := range []int32{cursor.Anchor, cursor.Head} {
+			p, err := s.history.TransformPosition(p, cursor.Version, api_pb.PositionUnit_CODE_POINTS, cursor.ConnectionId)
+			if err != nil {
+				return
+			}
+			pos[i] = p
+		}
+		cursor.Anchor, cursor.Head, cursor.Version = pos[0], pos[1], s.history.Version()
+	}
+	r.cursors[cursor.ConnectionId] = cursor
+
+	for cl := range s.clients {
+		if cl.can(api_pb.Capability_CURSORS) {
+			cl.send(api_pb.Event_CURSOR, cursorIn(s.text, cursor, cl.unit))
+		}
+	}
+}
+
+// moveCursors keeps stored cursors in place as rev changes the text around them.
+func (s *session) moveCursors(rev *ot.Revision) {
+	for cl := range s.clients {
+		if cl.cursor != nil {
+			moveCursor(cl.cursor, rev)
+		}
+	}
+	for _, r := range s.remote {
+		for _, cursor := range r.cursors {
+			moveCursor(cursor, rev)
+		}
+	}
+}
+
+func moveCursor(cursor *api_pb.Cursor, rev *ot.Revision) {
+	if cursor.Version >= rev.Version {
+		return
+	}
+	cursor.Anchor = ot.TransformPosition(cursor.Anchor, rev.Ops, cursor.ConnectionId)
+	cursor.Head = ot.TransformPosition(cursor.Head, rev.Ops, cursor.ConnectionId)
+	cursor.Version = rev.Version
+}
+
+// cursors returns the stored cursors of every client, including the ones
+// connected to other replicas, with positions counted in the unit of cl. Clients
+// that don't support cursors get none.
+func (s *session) cursors(cl *client) []*api_pb.Cursor {
+	if !cl.can(api_pb.Capability_CURSORS) {
+		return nil
+	}
+	var res []*api_pb.Cursor
+	for other := range s.clients {
+		if other.cursor != nil {
+			res = append(res, cursorIn(s.text, other.cursor, cl.unit))
+		}
+	}
+	for _, r := range s.remote {
+		for _, cursor := range r.cursors {
+			res = append(res, cursorIn(s.text, cursor, cl.unit))
+		}
+	}
+	return res
+}
+
+func cursorIn(text *ot.Buffer, cursor *api_pb.Cursor, unit api_pb.PositionUnit) *api_pb.Cursor {
+	return &api_pb.Cursor{
+		UserId:       cursor.UserId,
+		Anchor:       text.FromCodePoints(cursor.Anchor, unit),
+		Head:         text.FromCodePoints(cursor.Head, unit),
+		Version:      cursor.Version,
+		ConnectionId: cursor.ConnectionId,
+	}
+}
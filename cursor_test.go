@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"testing"
+)
+
+func TestCursorResync(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := &testClient{t: t, conn: dial(t, srv, doc.ID, "user")}
+	defer c.conn.Close()
+	readEvent(t, c.conn)
+
+	// the client claims a version the server never made
+	c.write(api_pb.Event_CURSOR, &api_pb.Cursor{Anchor: 1, Head: 1, Version: 9})
+	ev := readEvent(t, c.conn)
+	var e api_pb.Error
+	decoder.Unmarshal(bytes.NewReader(ev.Event), &e)
+	if ev.Type != api_pb.Event_ERROR || e.Code != api_pb.ErrorCode_UNKNOWN_VERSION || !e.Resync {
+		t.Fatalf("got %v %v", ev.Type, &e)
+	}
+	ev = readEvent(t, c.conn)
+	var resync api_pb.Resync
+	decoder.Unmarshal(bytes.NewReader(ev.Event), &resync)
+	if ev.Type != api_pb.Event_RESYNC || resync.Text != "abc" || resync.LastVersion != 0 {
+		t.Errorf("got %v %v after the error", ev.Type, &resync)
+	}
+}
@@ -1,7 +1,175 @@
 package main
 
+import (
+	"context"
+	"fmt"
+	"github.com/mitchellh/mapstructure"
+	"github.com/redis/go-redis/v9"
+	"github.com/ssau-fiit/cloudocs-api/common/util"
+	"github.com/ssau-fiit/cloudocs-api/database"
+	"strconv"
+	"time"
+)
+
 type Document struct {
 	ID     string `json:"ID" mapstructure:"id"`
 	Name   string `json:"name" mapstructure:"name"`
 	Author string `json:"author" mapstructure:"author"`
+	// Mode is how the document is edited, documents created before modes
+	// existed have none and use OT
+	Mode string `json:"mode,omitempty" mapstructure:"mode"`
+}
+
+// Document modes.
+const (
+	modeOT   = "ot"
+	modeCRDT = "crdt"
+)
+
+type Version struct {
+	Version int32     `json:"version"`
+	Author  string    `json:"author"`
+	Time    time.Time `json:"time"`
+}
+
+// Sessions write to the keys of their document long after it was loaded. The
+// scripts below only write while documents.<id> still exists, so a session
+// that is not closed yet can't bring back the keys of a deleted document.
+
+// setIfExists sets KEYS[2] to ARGV[1] if KEYS[1] exists. It returns nil if it
+// doesn't.
+var setIfExists = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return false
+end
+return redis.call("SET", KEYS[2], ARGV[1])
+`)
+
+// appendIfExists adds an entry to the KEYS[2] stream if KEYS[1] exists. ARGV
+// are the entry id and fields, like for XADD. It returns nil if KEYS[1]
+// doesn't exist.
+var appendIfExists = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return false
+end
+return redis.call("XADD", KEYS[2], unpack(ARGV))
+`)
+
+// saveText writes the text of the document to texts.<id>. It returns
+// errDocumentDeleted if the document no longer exists.
+func saveText(ctx context.Context, docID, text string) error {
+	keys := []string{fmt.Sprintf("documents.%v", docID), fmt.Sprintf("texts.%v", docID)}
+	err := setIfExists.Run(ctx, database.Database(), keys, text).Err()
+	if err == redis.Nil {
+		return errDocumentDeleted
+	}
+	return err
+}
+
+// appendEntry adds an entry with the given id and fields to a stream of the
+// document. It returns errDocumentDeleted if the document no longer exists.
+func appendEntry(ctx context.Context, docID, stream, id string, values ...any) error {
+	keys := []string{fmt.Sprintf("documents.%v", docID), stream}
+	err := appendIfExists.Run(ctx, database.Database(), keys, append([]any{id}, values...)...).Err()
+	if err == redis.Nil {
+		return errDocumentDeleted
+	}
+	return err
+}
+
+// getDocument reads the info of the document. It returns redis.Nil if the
+// document does not exist.
+func getDocument(ctx context.Context, docID string) (Document, error) {
+	var doc Document
+	res, err := database.Database().HGetAll(ctx, fmt.Sprintf("documents.%v", docID)).Result()
+	if err != nil {
+		return doc, err
+	}
+	if len(res) == 0 {
+		return doc, redis.Nil
+	}
+
+	err = mapstructure.Decode(res, &doc)
+	return doc, err
+}
+
+// listDocuments reads the info of every document.
+func listDocuments(ctx context.Context) ([]Document, error) {
+	db := database.Database()
+	keys, err := db.Keys(ctx, "documents.*").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []Document
+	for _, key := range keys {
+		docMap, err := db.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+
+		var doc Document
+		if err := mapstructure.Decode(docMap, &doc); err != nil {
+			return nil, err
+		}
+		documents = append(documents, doc)
+	}
+	return documents, nil
+}
+
+// createDocument stores a new document with a placeholder text. Documents use
+// OT unless mode says otherwise.
+func createDocument(ctx context.Context, name, author, mode string) (Document, error) {
+	if author == "" {
+		author = "Автор"
+	}
+	if mode == "" {
+		mode = modeOT
+	}
+
+	db := database.Database()
+	uid := util.GetRandomNumber()
+	_, err := db.HSet(ctx, fmt.Sprintf("documents.%v", uid), "id", uid, "name", name, "author", author, "mode", mode).Result()
+	if err != nil {
+		return Document{}, err
+	}
+
+	if err := db.Set(ctx, fmt.Sprintf("texts.%v", uid), "start typing", 0).Err(); err != nil {
+		db.HDel(ctx, fmt.Sprintf("documents.%v", uid))
+		return Document{}, err
+	}
+	if mode == modeCRDT {
+		if err := seedCRDT(ctx, strconv.Itoa(uid), "start typing"); err != nil {
+			db.Del(ctx, fmt.Sprintf("documents.%v", uid), fmt.Sprintf("texts.%v", uid))
+			return Document{}, err
+		}
+	}
+
+	return Document{
+		ID:     strconv.Itoa(uid),
+		Name:   name,
+		Author: author,
+		Mode:   mode,
+	}, nil
+}
+
+// deleteDocument removes the document with its text and operations. It
+// returns redis.Nil if the document does not exist.
+func deleteDocument(ctx context.Context, docID string) error {
+	db := database.Database()
+	exists, err := db.Exists(ctx, fmt.Sprintf("documents.%v", docID)).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return redis.Nil
+	}
+
+	if err := db.Del(ctx, fmt.Sprintf("documents.%v", docID)).Err(); err != nil {
+		return err
+	}
+	if err := db.Del(ctx, fmt.Sprintf("texts.%v", docID)).Err(); err != nil {
+		return err
+	}
+	return db.Del(ctx, opsKey(docID), snapshotKey(docID), crdtKey(docID)).Err()
 }
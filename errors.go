@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"github.com/ssau-fiit/cloudocs-api/crdt"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+var (
+	errMalformedEvent     = errors.New("malformed event")
+	errUnsupportedEvent   = errors.New("unsupported event")
+	errIncompatibleClient = errors.New("incompatible client")
+	errNothingToUndo      = errors.New("nothing to undo")
+	errDocumentDeleted    = errors.New("document deleted")
+	errWriteTimeout       = errors.New("write timed out")
+)
+
+// remoteError is an error another replica reported for an operation it was
+// forwarded.
+type remoteError struct {
+	code    api_pb.ErrorCode
+	message string
+	resync  bool
+}
+
+func (e *remoteError) Error() string {
+	return e.message
+}
+
+// errorCode maps an error to the code reported to clients and tells whether
+// the client should resync its state with the server.
+func errorCode(err error) (api_pb.ErrorCode, bool) {
+	var remote *remoteError
+	switch {
+	case errors.As(err, &remote):
+		return remote.code, remote.resync
+	case errors.Is(err, errMalformedEvent):
+		return api_pb.ErrorCode_MALFORMED_EVENT, false
+	case errors.Is(err, errUnsupportedEvent):
+		return api_pb.ErrorCode_UNSUPPORTED_EVENT, false
+	case errors.Is(err, errIncompatibleClient):
+		return api_pb.ErrorCode_INCOMPATIBLE_CLIENT, false
+	case errors.Is(err, errNothingToUndo):
+		return api_pb.ErrorCode_NOTHING_TO_UNDO, false
+	case errors.Is(err, errDocumentDeleted):
+		return api_pb.ErrorCode_DOCUMENT_DELETED, false
+	case errors.Is(err, ot.ErrInvalid), errors.Is(err, crdt.ErrInvalid):
+		return api_pb.ErrorCode_INVALID_OPERATION, false
+	case errors.Is(err, crdt.ErrMissing):
+		return api_pb.ErrorCode_CRDT_SYNC_REQUIRED, false
+	case errors.Is(err, ot.ErrOutOfRange), errors.Is(err, ot.ErrSplitCharacter):
+		return api_pb.ErrorCode_OUT_OF_RANGE, true
+	case errors.Is(err, ot.ErrUnknownVersion):
+		return api_pb.ErrorCode_UNKNOWN_VERSION, true
+	case errors.Is(err, ot.ErrCompacted):
+		return api_pb.ErrorCode_VERSION_COMPACTED, true
+	default:
+		return api_pb.ErrorCode_INTERNAL, false
+	}
+}
+
+// fail reports err to the client. requestID and op are the request and the
+// operation that caused it, if any.
+func (cl *client) fail(requestID string, err error, op *api_pb.Operation) {
+	code, resync := errorCode(err)
+	cl.reply(requestID, api_pb.Event_ERROR, &api_pb.Error{
+		Code:      code,
+		Message:   err.Error(),
+		Operation: op,
+		Resync:    resync,
+	})
+}
+
+// fail reports err to cl like client.fail does, and sends it the current text
+// if the error asks it to resync.
+func (s *session) fail(cl *client, requestID string, err error, op *api_pb.Operation) {
+	cl.fail(requestID, err, op)
+	if _, resync := errorCode(err); resync {
+		s.resync(cl)
+	}
+}
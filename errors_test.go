@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"github.com/ssau-fiit/cloudocs-api/crdt"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   api_pb.ErrorCode
+		resync bool
+	}{
+		{fmt.Errorf("%w: bad", ot.ErrInvalid), api_pb.ErrorCode_INVALID_OPERATION, false},
+		{fmt.Errorf("%w: bad", crdt.ErrInvalid), api_pb.ErrorCode_INVALID_OPERATION, false},
+		{fmt.Errorf("%w: missing", crdt.ErrMissing), api_pb.ErrorCode_CRDT_SYNC_REQUIRED, false},
+		{ot.ErrOutOfRange, api_pb.ErrorCode_OUT_OF_RANGE, true},
+		{ot.ErrCompacted, api_pb.ErrorCode_VERSION_COMPACTED, true},
+		{errDocumentDeleted, api_pb.ErrorCode_DOCUMENT_DELETED, false},
+		{&remoteError{code: api_pb.ErrorCode_UNKNOWN_VERSION, resync: true}, api_pb.ErrorCode_UNKNOWN_VERSION, true},
+		{fmt.Errorf("redis down"), api_pb.ErrorCode_INTERNAL, false},
+	}
+	for _, tt := range tests {
+		if code, resync := errorCode(tt.err); code != tt.code || resync != tt.resync {
+			t.Errorf("%v: got %v %v, want %v %v", tt.err, code, resync, tt.code, tt.resync)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"github.com/rs/zerolog/log"
+	"sync"
+	"time"
+)
+
+// Flush metrics are published with expvar under /debug/vars.
+var (
+	// dirtyDocuments is the number of open documents whose text changed since
+	// it was last written to texts.<id>
+	dirtyDocuments = expvar.NewInt("dirty_documents")
+	flushErrors    = expvar.NewInt("flush_errors")
+	flushes        = &flushStats{}
+)
+
+func init() {
+	expvar.Publish("flushes", expvar.Func(flushes.value))
+}
+
+// flushStats keeps the number of flushes and how long they took.
+type flushStats struct {
+	mu    sync.Mutex
+	count int64
+	total time.Duration
+	last  time.Duration
+	max   time.Duration
+}
+
+func (f *flushStats) observe(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.count++
+	f.total += d
+	f.last = d
+	if d > f.max {
+		f.max = d
+	}
+}
+
+func (f *flushStats) value() any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var avg time.Duration
+	if f.count > 0 {
+		avg = f.total / time.Duration(f.count)
+	}
+	return map[string]any{
+		"count":           f.count,
+		"latency_last_ms": f.last.Seconds() * 1000,
+		"latency_avg_ms":  avg.Seconds() * 1000,
+		"latency_max_ms":  f.max.Seconds() * 1000,
+	}
+}
+
+// markDirty records that the text of the session has to be flushed.
+func (s *session) markDirty() {
+	if !s.dirty {
+		s.dirty = true
+		dirtyDocuments.Add(1)
+	}
+}
+
+// flush writes the text of the session to texts.<id> if it changed. A failed
+// flush is retried on the next tick, while a deleted document closes the
+// session.
+func (s *session) flush() {
+	if !s.dirty {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	start := time.Now()
+	err := saveText(ctx, s.doc.ID, s.text.String())
+	if errors.Is(err, errDocumentDeleted) {
+		s.closeDeleted()
+		return
+	}
+	if err != nil {
+		flushErrors.Add(1)
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error flushing document text")
+		return
+	}
+	flushes.observe(time.Since(start))
+
+	s.dirty = false
+	dirtyDocuments.Add(-1)
+}
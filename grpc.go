@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"github.com/golang/protobuf/proto"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"strings"
+	"time"
+)
+
+// collabServer implements CollabService on top of the same document sessions
+// as the websocket endpoint.
+type collabServer struct {
+	api_pb.UnimplementedCollabServiceServer
+}
+
+func newGRPCServer() *grpc.Server {
+	srv := grpc.NewServer(grpc.KeepaliveParams(keepalive.ServerParameters{
+		Time:    pingInterval,
+		Timeout: pongTimeout,
+	}))
+	api_pb.RegisterCollabServiceServer(srv, &collabServer{})
+	return srv
+}
+
+func documentProto(doc Document) *api_pb.Document {
+	return &api_pb.Document{
+		Id:     doc.ID,
+		Name:   doc.Name,
+		Author: doc.Author,
+		Mode:   api_pb.DocumentMode(api_pb.DocumentMode_value[strings.ToUpper(doc.Mode)]),
+	}
+}
+
+func (*collabServer) ListDocuments(ctx context.Context, _ *api_pb.ListDocumentsRequest) (*api_pb.ListDocumentsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	documents, err := listDocuments(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("error listing documents")
+		return nil, status.Error(codes.Internal, "error listing documents")
+	}
+
+	res := &api_pb.ListDocumentsResponse{}
+	for _, doc := range documents {
+		res.Documents = append(res.Documents, documentProto(doc))
+	}
+	return res, nil
+}
+
+func (*collabServer) CreateDocument(ctx context.Context, r *api_pb.CreateDocumentRequest) (*api_pb.Document, error) {
+	mode := strings.ToLower(r.Mode.String())
+	if mode != modeOT && mode != modeCRDT {
+		return nil, status.Error(codes.InvalidArgument, "unknown document mode")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	doc, err := createDocument(ctx, r.Name, r.Author, mode)
+	if err != nil {
+		log.Error().Err(err).Msg("error creating document")
+		return nil, status.Error(codes.Internal, "error creating document")
+	}
+	return documentProto(doc), nil
+}
+
+func (*collabServer) DeleteDocument(ctx context.Context, r *api_pb.DeleteDocumentRequest) (*api_pb.DeleteDocumentResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	err := deleteDocument(ctx, r.Id)
+	if err == redis.Nil {
+		return nil, status.Error(codes.NotFound, "document not found")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("error deleting document")
+		return nil, status.Error(codes.Internal, "error deleting document")
+	}
+	sessions.deleted(r.Id)
+	return &api_pb.DeleteDocumentResponse{}, nil
+}
+
+func (*collabServer) Edit(stream api_pb.CollabService_EditServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	get := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	docID := get("document-id")
+	if docID == "" {
+		return status.Error(codes.InvalidArgument, "missing document-id")
+	}
+	clientID := get("x-cloudocs-id")
+	if clientID == "" {
+		return status.Error(codes.Unauthenticated, "missing x-cloudocs-id")
+	}
+	name := get("name")
+	if name == "" {
+		name = clientID
+	}
+	unit, err := parseUnit(get("unit"))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	resume, err := parseResume(get("version"))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(stream.Context(), time.Second*5)
+	doc, err := getDocument(ctx, docID)
+	cancel()
+	if err == redis.Nil {
+		return status.Error(codes.NotFound, "document not found")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("error getting document")
+		return status.Error(codes.Internal, "error getting document")
+	}
+
+	codec := eventCodec{protoFormat{}}
+	cl := newClient(clientID, name, &editStream{stream}, codec, unit)
+	cl.resume = resume
+	defer cl.close()
+
+	session := sessions.join(doc, cl)
+	defer sessions.leave(session, cl)
+
+	// only the handler may send on the stream, so it runs the writer and
+	// receives in another goroutine, which closes the client once the stream
+	// ends
+	go func() {
+		defer cl.close()
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && status.Code(err) != codes.Canceled {
+					log.Error().Err(err).Msg("failed to receive event from client")
+				}
+				return
+			}
+
+			payload, err := codec.payload(ev)
+			if err != nil {
+				log.Error().Err(err).Msg("error decoding event")
+				cl.fail("", err, nil)
+				continue
+			}
+			session.submit(cl, "", payload)
+		}
+	}()
+	cl.writer()
+	return nil
+}
+
+// editStream is the transport of Edit clients.
+type editStream struct {
+	stream api_pb.CollabService_EditServer
+}
+
+// write sends an event, giving up after writeTimeout. The writer stops on
+// the error and Edit returns, which ends the stream and with it the Send still
+// waiting for the client.
+func (s *editStream) write(data []byte) error {
+	// events are queued encoded, as for websocket clients
+	var ev api_pb.Event
+	if err := proto.Unmarshal(data, &ev); err != nil {
+		return err
+	}
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- s.stream.Send(&ev)
+	}()
+	timer := time.NewTimer(writeTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-sent:
+		return err
+	case <-timer.C:
+		return errWriteTimeout
+	}
+}
+
+// ping does nothing, the server keepalive checks on the connection.
+func (s *editStream) ping() error {
+	return nil
+}
+
+// close does nothing, the stream ends once Edit returns after the writer
+// stops.
+func (s *editStream) close() {}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestCreateDocumentMode(t *testing.T) {
+	_, err := (&collabServer{}).CreateDocument(context.Background(), &api_pb.CreateDocumentRequest{Name: "test", Mode: 7})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v for an unknown mode", err)
+	}
+	doc, err := (&collabServer{}).CreateDocument(context.Background(), &api_pb.CreateDocumentRequest{Name: "test", Mode: api_pb.DocumentMode_CRDT})
+	if err != nil || doc.Mode != api_pb.DocumentMode_CRDT {
+		t.Errorf("got %v, %v", doc, err)
+	}
+}
+
+// stuckStream is an Edit stream whose client never reads.
+type stuckStream struct {
+	api_pb.CollabService_EditServer
+	ctx context.Context
+}
+
+func (s *stuckStream) Send(*api_pb.Event) error {
+	<-s.ctx.Done()
+	return s.ctx.Err()
+}
+
+func TestEditStreamWriteTimeout(t *testing.T) {
+	old := writeTimeout
+	writeTimeout = 50 * time.Millisecond
+	defer func() { writeTimeout = old }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &editStream{stream: &stuckStream{ctx: ctx}}
+	if err := s.write(nil); !errors.Is(err, errWriteTimeout) {
+		t.Errorf("got %v, want a timeout", err)
+	}
+}
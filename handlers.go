@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/mitchellh/mapstructure"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
-	"github.com/ssau-fiit/cloudocs-api/common/util"
 	"github.com/ssau-fiit/cloudocs-api/database"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
 	"net/http"
 	"strconv"
 	"time"
@@ -17,6 +20,7 @@ import (
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
+	Subprotocols:    protocols,
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
@@ -71,33 +75,13 @@ func handleGetDocuments(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	db := database.Database()
-	keys, err := db.Keys(ctx, "documents.*").Result()
+	documents, err := listDocuments(ctx)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to get document keys")
+		log.Error().Err(err).Msg("error listing documents")
 		c.AbortWithStatus(500)
 		return
 	}
 
-	var documents []Document
-	for _, key := range keys {
-		docMap, err := database.Database().HGetAll(ctx, key).Result()
-		if err != nil {
-			log.Error().Err(err).Msg("error getting document")
-			c.AbortWithStatus(500)
-			return
-		}
-
-		var doc Document
-		err = mapstructure.Decode(docMap, &doc)
-		if err != nil {
-			log.Error().Err(err).Msg("error decoding map")
-			c.AbortWithStatus(500)
-			return
-		}
-		documents = append(documents, doc)
-	}
-
 	c.JSON(200, documents)
 }
 
@@ -109,35 +93,23 @@ func handleCreateDocument(c *gin.Context) {
 		c.AbortWithStatus(500)
 		return
 	}
-	if r.Author == "" {
-		r.Author = "Автор"
-	}
-
-	db := database.Database()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	uid := util.GetRandomNumber()
-	_, err = db.HSet(ctx, fmt.Sprintf("documents.%v", uid), "id", uid, "name", r.Name, "author", r.Author).Result()
-	if err != nil {
-		log.Error().Err(err).Msg("error uploading document")
-		c.AbortWithStatus(500)
+	if r.Mode != "" && r.Mode != modeOT && r.Mode != modeCRDT {
+		c.AbortWithStatus(http.StatusBadRequest)
 		return
 	}
 
-	if err := db.Set(ctx, fmt.Sprintf("texts.%v", uid), "start typing", 0).Err(); err != nil {
-		log.Error().Err(err).Msg("error creating document text")
-		db.HDel(ctx, fmt.Sprintf("documents.%v", uid))
-		c.AbortWithStatus(http.StatusInternalServerError)
+	doc, err := createDocument(ctx, r.Name, r.Author, r.Mode)
+	if err != nil {
+		log.Error().Err(err).Msg("error creating document")
+		c.AbortWithStatus(500)
 		return
 	}
 
-	c.JSON(200, Document{
-		ID:     strconv.Itoa(uid),
-		Name:   r.Name,
-		Author: r.Author,
-	})
+	c.JSON(200, doc)
 }
 
 func handleDeleteDocument(c *gin.Context) {
@@ -150,26 +122,255 @@ func handleDeleteDocument(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	if exists, err := database.Database().
-		Exists(ctx, fmt.Sprintf("documents.%v", docID)).
-		Result(); exists == 0 || err != nil {
+	err := deleteDocument(ctx, docID)
+	if err == redis.Nil {
 		c.AbortWithStatus(http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		log.Error().Err(err).Msg("error deleting document")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	sessions.deleted(docID)
+
+	c.Status(200)
+}
+
+/////////////////////////////
+/// History Handlers
+/////////////////////////////
+
+func handleGetHistory(c *gin.Context) {
+	docID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if _, ok := versionedDocument(ctx, c, docID); !ok {
+		return
+	}
 
-	_, err := database.Database().Del(ctx, fmt.Sprintf("documents.%v", docID)).Result()
+	history, err := loadHistory(ctx, docID, 0)
 	if err != nil {
-		log.Error().Err(err).Msg("error deleting document info")
+		log.Error().Err(err).Msg("error loading document history")
 		c.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
+	revs, _ := history.Since(history.Base())
 
-	_, err = database.Database().Del(ctx, fmt.Sprintf("texts.%v", docID)).Result()
+	versions := make([]Version, 0, len(revs))
+	for _, rev := range revs {
+		versions = append(versions, Version{
+			Version: rev.Version,
+			Author:  rev.UserID,
+			Time:    rev.Time,
+		})
+	}
+
+	c.JSON(200, versions)
+}
+
+func handleGetVersion(c *gin.Context) {
+	docID := c.Param("id")
+	version, err := strconv.ParseInt(c.Param("v"), 10, 32)
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if _, ok := versionedDocument(ctx, c, docID); !ok {
+		return
+	}
+
+	text, err := textAt(ctx, docID, int32(version))
+	if !versionFound(c, err) {
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"version": version,
+		"text":    text,
+	})
+}
+
+func handleRestoreDocument(c *gin.Context) {
+	docID := c.Param("id")
+	var r RestoreRequest
+	err := c.BindJSON(&r)
 	if err != nil {
-		log.Error().Err(err).Msg("error deleting document text")
+		log.Error().Err(err).Msg("bad request")
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	doc, ok := versionedDocument(ctx, c, docID)
+	if !ok {
+		return
+	}
+
+	text, err := textAt(ctx, docID, r.Version)
+	if !versionFound(c, err) {
+		return
+	}
+
+	s := sessions.acquire(doc)
+	defer sessions.release(s)
+
+	type result struct {
+		version int32
+		err     error
+	}
+	res := make(chan result, 1)
+	s.do(func() {
+		s.replace(c.GetHeader("X-Cloudocs-ID"), text, func(version int32, err error) {
+			res <- result{version, err}
+		})
+	})
+
+	// the sequencer may be another replica, so the revision can take a while
+	var restored result
+	select {
+	case restored = <-res:
+	case <-ctx.Done():
+		restored.err = ctx.Err()
+	}
+	if restored.err != nil {
+		log.Error().Err(restored.err).Msg("error restoring document")
 		c.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
 
-	c.Status(200)
+	c.JSON(200, gin.H{
+		"version": restored.version,
+	})
+}
+
+// versionedDocument reads the info of a document that keeps versions and
+// aborts the request if there is no such document. Documents in CRDT mode have
+// no versions.
+func versionedDocument(ctx context.Context, c *gin.Context, docID string) (Document, bool) {
+	doc, err := getDocument(ctx, docID)
+	switch {
+	case err == redis.Nil:
+		c.AbortWithStatus(http.StatusNotFound)
+	case err != nil:
+		log.Error().Err(err).Msg("error getting document")
+		c.AbortWithStatus(http.StatusInternalServerError)
+	case doc.Mode == modeCRDT:
+		c.AbortWithStatus(http.StatusConflict)
+	default:
+		return doc, true
+	}
+	return doc, false
+}
+
+// versionFound aborts the request if err means the version can't be read.
+func versionFound(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ot.ErrUnknownVersion):
+		c.AbortWithStatus(http.StatusNotFound)
+	case errors.Is(err, ot.ErrCompacted):
+		c.AbortWithStatus(http.StatusGone)
+	default:
+		log.Error().Err(err).Msg("error reading document version")
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}
+	return false
+}
+
+/////////////////////////////
+/// Sync Handlers
+/////////////////////////////
+
+// handleSyncDocument commits the operations a client made while offline. The
+// request and response are SyncRequest and SyncResponse, encoded as JSON like
+// the events of the websocket.
+func handleSyncDocument(c *gin.Context) {
+	docID := c.Param("id")
+	clientID := c.GetHeader("X-Cloudocs-ID")
+	if clientID == "" {
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+	var r api_pb.SyncRequest
+	if err := decoder.Unmarshal(c.Request.Body, &r); err != nil {
+		log.Error().Err(err).Msg("bad request")
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	doc, ok := versionedDocument(ctx, c, docID)
+	if !ok {
+		return
+	}
+
+	s := sessions.acquire(doc)
+	defer sessions.release(s)
+
+	type result struct {
+		res *api_pb.SyncResponse
+		err error
+	}
+	res := make(chan result, 1)
+	s.do(func() {
+		s.syncOffline(clientID, &r, func(sr *api_pb.SyncResponse, err error) {
+			res <- result{sr, err}
+		})
+	})
+
+	// the sequencer may be another replica, so the revision can take a while
+	var synced result
+	select {
+	case synced = <-res:
+	case <-ctx.Done():
+		synced.err = ctx.Err()
+	}
+	if synced.err != nil {
+		syncFailed(c, synced.err)
+		return
+	}
+
+	body, err := encoder.MarshalToString(synced.res)
+	if err != nil {
+		log.Error().Err(err).Msg("error encoding sync response")
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.Data(200, "application/json", []byte(body))
+}
+
+// syncFailed aborts a sync request with the status matching err and the error
+// in the body.
+func syncFailed(c *gin.Context, err error) {
+	code, _ := errorCode(err)
+	status := http.StatusInternalServerError
+	switch code {
+	case api_pb.ErrorCode_INVALID_OPERATION, api_pb.ErrorCode_OUT_OF_RANGE:
+		status = http.StatusBadRequest
+	case api_pb.ErrorCode_UNKNOWN_VERSION:
+		status = http.StatusConflict
+	case api_pb.ErrorCode_VERSION_COMPACTED:
+		status = http.StatusGone
+	default:
+		log.Error().Err(err).Msg("error syncing document")
+	}
+
+	body, _ := encoder.MarshalToString(&api_pb.Error{
+		Code:    code,
+		Message: err.Error(),
+	})
+	c.Data(status, "application/json", []byte(body))
+	c.Abort()
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"time"
+)
+
+// protocolVersion is the version of the websocket protocol the server speaks.
+// Clients older than minProtocolVersion are rejected. Clients of the v1
+// subprotocols don't send Hello and speak version 1.
+const (
+	protocolVersion    = 2
+	minProtocolVersion = 2
+)
+
+// capabilities returns the capabilities the server supports on a connection
+// using c.
+func capabilities(c codec) []api_pb.Capability {
+	return append([]api_pb.Capability{api_pb.Capability_BATCHING}, baseline(c)...)
+}
+
+// baseline returns the capabilities of a connection using c that did not
+// negotiate any, which is what clients spoke before Hello existed.
+func baseline(c codec) []api_pb.Capability {
+	res := []api_pb.Capability{api_pb.Capability_CURSORS}
+	if c.messageType() == websocket.BinaryMessage {
+		res = append(res, api_pb.Capability_BINARY)
+	}
+	return res
+}
+
+// handshake waits for the Hello of a client using one of the v2 subprotocols
+// and negotiates the protocol version, position unit and capabilities of the
+// connection. Incompatible clients are rejected. Clients of the v1
+// subprotocols keep the baseline capabilities.
+func (cl *client) handshake(conn *websocket.Conn) error {
+	if !cl.codec.hello() {
+		return nil
+	}
+
+	conn.SetReadDeadline(time.Now().Add(helloTimeout))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return err
+	}
+	requestID, msg, err := cl.codec.decode(data)
+	if err == nil {
+		hello, ok := msg.(*api_pb.Hello)
+		if !ok {
+			err = fmt.Errorf("%w: expected HELLO", errIncompatibleClient)
+		} else {
+			err = cl.negotiate(hello)
+		}
+	}
+	if err != nil {
+		cl.reject(conn, requestID, err)
+		return err
+	}
+	cl.hello = requestID
+	return nil
+}
+
+func (cl *client) negotiate(hello *api_pb.Hello) error {
+	if hello.ProtocolVersion < minProtocolVersion {
+		return fmt.Errorf("%w: protocol version %v is not supported, the oldest supported is %v",
+			errIncompatibleClient, hello.ProtocolVersion, minProtocolVersion)
+	}
+	if _, ok := api_pb.PositionUnit_name[int32(hello.PositionUnit)]; !ok {
+		return fmt.Errorf("%w: unknown position unit %v", errIncompatibleClient, hello.PositionUnit)
+	}
+
+	cl.version = protocolVersion
+	if hello.ProtocolVersion < cl.version {
+		cl.version = hello.ProtocolVersion
+	}
+	cl.unit = hello.PositionUnit
+	cl.capabilities = nil
+	wanted := make(map[api_pb.Capability]bool, len(hello.Capabilities))
+	for _, c := range hello.Capabilities {
+		wanted[c] = true
+	}
+	for _, c := range capabilities(cl.codec) {
+		// the subprotocol already decided the encoding
+		if wanted[c] || c == api_pb.Capability_BINARY {
+			cl.capabilities = append(cl.capabilities, c)
+		}
+	}
+	return nil
+}
+
+// reject tells the client why it can't connect and closes the connection. The
+// writer is not running yet, so the error is written directly.
+func (cl *client) reject(conn *websocket.Conn, requestID string, err error) {
+	code, _ := errorCode(err)
+	data, _ := cl.codec.encode(requestID, api_pb.Event_ERROR, &api_pb.Error{
+		Code:    code,
+		Message: err.Error(),
+	})
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	conn.WriteMessage(cl.codec.messageType(), data)
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, ""))
+}
+
+// can reports whether the capability was negotiated for the connection.
+func (cl *client) can(c api_pb.Capability) bool {
+	for _, have := range cl.capabilities {
+		if have == c {
+			return true
+		}
+	}
+	return false
+}
+
+// accepts reports whether the client may send msg with the capabilities it
+// negotiated.
+func (cl *client) accepts(msg proto.Message) bool {
+	switch msg.(type) {
+	case *api_pb.OperationBatch:
+		// v1 clients could send batches before capabilities existed
+		return cl.can(api_pb.Capability_BATCHING) || cl.version == 1
+	case *api_pb.Cursor:
+		return cl.can(api_pb.Capability_CURSORS)
+	case *api_pb.Hello:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"strings"
+	"sync"
+)
+
+// hub keeps a session for every document that has at least one connected
+// client or a pending request. It listens to the channels of all documents
+// at once and hands the messages over to their sessions.
+type hub struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	refs     map[*session]int
+	// sub is the subscription to the document channels, made with the first
+	// session
+	sub *redis.PubSub
+}
+
+func newHub() *hub {
+	return &hub{
+		sessions: make(map[string]*session),
+		refs:     make(map[*session]int),
+	}
+}
+
+// acquire returns the session of doc, starting it if needed. Every acquire has
+// to be paired with a release.
+func (h *hub) acquire(doc Document) *session {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.sessions[doc.ID]
+	if !ok {
+		h.listen()
+		s = newSession(doc)
+		h.sessions[doc.ID] = s
+		go s.run()
+	}
+	h.refs[s]++
+	return s
+}
+
+// listen subscribes to the document channels unless the hub already did. A
+// session started without the subscription gets no cluster messages, the next
+// one tries again.
+func (h *hub) listen() {
+	if h.sub != nil {
+		return
+	}
+	sub, err := subscribe()
+	if err != nil {
+		log.Error().Err(err).Msg("error subscribing to document channels")
+		return
+	}
+	h.sub = sub
+	go h.route(sub.Channel())
+}
+
+// route hands the messages published on the document channels over to the
+// sessions of their documents.
+func (h *hub) route(messages <-chan *redis.Message) {
+	for msg := range messages {
+		h.mu.Lock()
+		s, ok := h.sessions[strings.TrimPrefix(msg.Channel, channelKey(""))]
+		h.mu.Unlock()
+		if !ok {
+			continue
+		}
+		select {
+		case s.cluster <- msg:
+		case <-s.done:
+		}
+	}
+}
+
+// release stops the session once nobody uses it anymore.
+func (h *hub) release(s *session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.refs[s]--
+	if h.refs[s] == 0 {
+		delete(h.refs, s)
+		delete(h.sessions, s.doc.ID)
+		close(s.done)
+	}
+}
+
+// shutdown stops every session and waits until they have flushed their text.
+func (h *hub) shutdown() {
+	h.mu.Lock()
+	stopping := make([]*session, 0, len(h.sessions))
+	for id, s := range h.sessions {
+		delete(h.sessions, id)
+		delete(h.refs, s)
+		close(s.done)
+		stopping = append(stopping, s)
+	}
+	h.mu.Unlock()
+
+	for _, s := range stopping {
+		<-s.stopped
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.sub != nil {
+		h.sub.Close()
+		h.sub = nil
+	}
+}
+
+// deleted closes the sessions of the deleted document docID, on this replica
+// and on the others.
+func (h *hub) deleted(docID string) {
+	publish(docID, &clusterMessage{Kind: kindDeleted, Instance: instanceID})
+
+	h.mu.Lock()
+	s, ok := h.sessions[docID]
+	if ok {
+		h.refs[s]++
+	}
+	h.mu.Unlock()
+	if ok {
+		s.do(s.closeDeleted)
+		h.release(s)
+	}
+}
+
+// join registers the client in the session of doc.
+func (h *hub) join(doc Document, cl *client) *session {
+	s := h.acquire(doc)
+	s.join <- cl
+	return s
+}
+
+// leave removes the client from its session.
+func (h *hub) leave(s *session, cl *client) {
+	s.leave <- cl
+	h.release(s)
+}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	"github.com/ssau-fiit/cloudocs-api/database"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var redisServer *miniredis.Miniredis
+
+func TestMain(m *testing.M) {
+	var err error
+	redisServer, err = miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("REDIS_ADDR", redisServer.Addr())
+	database.Database()
+	gin.SetMode(gin.ReleaseMode)
+
+	code := m.Run()
+	redisServer.Close()
+	os.Exit(code)
+}
+
+func newTestServer() *httptest.Server {
+	r := gin.New()
+	v1 := r.Group("/api/v1")
+	v1.GET("/documents/:id", handleSocket)
+	v1.DELETE("/documents/:id", handleDeleteDocument)
+	v1.GET("/documents/:id/versions/:v", handleGetVersion)
+	v1.POST("/documents/:id/restore", handleRestoreDocument)
+	v1.POST("/documents/:id/sync", handleSyncDocument)
+	return httptest.NewServer(r)
+}
+
+func newTestDocument(t testing.TB, text string) Document {
+	t.Helper()
+	id := fmt.Sprint(rand.Int31())
+	redisServer.HSet("documents."+id, "id", id, "name", "test", "author", "test")
+	redisServer.Set("texts."+id, text)
+	return Document{ID: id, Name: "test", Author: "test"}
+}
+
+// testClient is a websocket client keeping its own copy of the text, with at
+// most one operation waiting for its ack.
+type testClient struct {
+	t       *testing.T
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	text    string
+	version int32
+	pending []*api_pb.Operation
+	// ready gets a value on Init and on every ack
+	ready chan struct{}
+}
+
+// dial connects to the document with the v1 JSON protocol.
+func dial(t *testing.T, srv *httptest.Server, docID, user string) *websocket.Conn {
+	t.Helper()
+	url := strings.Replace(srv.URL, "http", "ws", 1) + "/api/v1/documents/" + docID
+	conn, _, err := websocket.DefaultDialer.Dial(url, map[string][]string{"X-Cloudocs-ID": {user}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// readEvent reads the next event of conn.
+func readEvent(t *testing.T, conn *websocket.Conn) *api_pb.Event {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ev api_pb.Event
+	if err := decoder.Unmarshal(bytes.NewReader(data), &ev); err != nil {
+		t.Fatal(err)
+	}
+	return &ev
+}
+
+func dialTestClient(t *testing.T, srv *httptest.Server, docID, user string) *testClient {
+	t.Helper()
+	c := &testClient{t: t, conn: dial(t, srv, docID, user), ready: make(chan struct{}, 1)}
+	go c.read()
+	c.wait()
+	return c
+}
+
+func (c *testClient) wait() {
+	select {
+	case <-c.ready:
+	case <-time.After(10 * time.Second):
+		c.t.Error("timeout waiting for the server")
+	}
+}
+
+func (c *testClient) read() {
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var ev api_pb.Event
+		if err := decoder.Unmarshal(bytes.NewReader(data), &ev); err != nil {
+			c.t.Errorf("decoding event: %v", err)
+			return
+		}
+
+		c.mu.Lock()
+		switch ev.Type {
+		case api_pb.Event_INIT:
+			var init api_pb.Init
+			decoder.Unmarshal(bytes.NewReader(ev.Event), &init)
+			c.text, c.version = init.Text, init.LastVersion
+			c.ready <- struct{}{}
+		case api_pb.Event_OPERATION:
+			var op api_pb.Operation
+			decoder.Unmarshal(bytes.NewReader(ev.Event), &op)
+			c.receive([]*api_pb.Operation{&op}, op.Version)
+		case api_pb.Event_OPERATION_BATCH:
+			var batch api_pb.OperationBatch
+			decoder.Unmarshal(bytes.NewReader(ev.Event), &batch)
+			c.receive(batch.Operations, batch.Version)
+		case api_pb.Event_OPERATION_ACK:
+			var ack api_pb.OperationAck
+			decoder.Unmarshal(bytes.NewReader(ev.Event), &ack)
+			c.version = ack.LastVersion
+			c.pending = nil
+			c.ready <- struct{}{}
+		case api_pb.Event_ERROR:
+			c.t.Errorf("server error: %s", ev.Event)
+		}
+		c.mu.Unlock()
+	}
+}
+
+// receive applies operations of another client on top of the pending one.
+func (c *testClient) receive(ops []*api_pb.Operation, version int32) {
+	c.pending, ops = ot.Transform(c.pending, ops)
+	text, _, err := ot.Apply(c.text, ops, api_pb.PositionUnit_CODE_POINTS)
+	if err != nil {
+		c.t.Errorf("applying %v: %v", ops, err)
+		return
+	}
+	c.text, c.version = text, version
+}
+
+// edit makes a random edit and sends it to the server.
+func (c *testClient) edit(r *rand.Rand) {
+	c.send(func(n int32) *api_pb.Operation {
+		if n > 0 && r.Intn(3) == 0 {
+			return &api_pb.Operation{Type: api_pb.OpType_DELETE, Index: r.Int31n(n), Len: 1}
+		}
+		return &api_pb.Operation{Type: api_pb.OpType_INSERT, Index: r.Int31n(n + 1), Text: "ab", Len: 2}
+	})
+}
+
+func (c *testClient) insert(index int32, text string) {
+	c.send(func(int32) *api_pb.Operation {
+		return &api_pb.Operation{Type: api_pb.OpType_INSERT, Index: index, Text: text}
+	})
+}
+
+func (c *testClient) delete(index, n int32) {
+	c.send(func(int32) *api_pb.Operation {
+		return &api_pb.Operation{Type: api_pb.OpType_DELETE, Index: index, Len: n}
+	})
+}
+
+// send makes an operation on the text of the client, given its length, and
+// sends it to the server, waiting for the ack.
+func (c *testClient) send(makeOp func(n int32) *api_pb.Operation) {
+	c.mu.Lock()
+	op := makeOp(ot.Length(c.text, api_pb.PositionUnit_CODE_POINTS))
+	op.Version = c.version
+	text, _, err := ot.Apply(c.text, []*api_pb.Operation{op}, api_pb.PositionUnit_CODE_POINTS)
+	if err != nil {
+		c.t.Errorf("applying %v: %v", op, err)
+	}
+	c.text = text
+	c.pending = []*api_pb.Operation{op}
+	c.mu.Unlock()
+
+	c.write(api_pb.Event_OPERATION, op)
+	c.wait()
+}
+
+// write sends an event without waiting for anything.
+func (c *testClient) write(t api_pb.Event_EventType, msg proto.Message) {
+	payload, _ := encoder.MarshalToString(msg)
+	data, _ := encoder.MarshalToString(&api_pb.Event{Type: t, Event: []byte(payload)})
+	c.conn.WriteMessage(websocket.TextMessage, []byte(data))
+}
+
+// current returns the text of the client.
+func (c *testClient) current() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.text
+}
+
+// request sends an HTTP request to srv on behalf of user and returns the
+// status and body of the response.
+func request(t *testing.T, srv *httptest.Server, method, path, user, body string) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Cloudocs-ID", user)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, data
+}
+
+// waitFor waits until cond holds and reports whether it did.
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return false
+}
+
+// waitText waits until the text of the document flushed to Redis is want.
+func waitText(docID, want string) string {
+	var text string
+	for i := 0; i < 100; i++ {
+		if text, _ = redisServer.Get("texts." + docID); text == want {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return text
+}
+
+// compact snapshots the document as if all of its operations had outlived the
+// retention window, so they are dropped from the log and the history.
+func compact(t *testing.T, doc Document) {
+	t.Helper()
+	s := sessions.acquire(doc)
+	defer sessions.release(s)
+	var err error
+	s.do(func() {
+		revs, _ := s.history.Since(s.history.Base())
+		for _, rev := range revs {
+			rev.Time = rev.Time.Add(-2 * opsRetention)
+		}
+		err = saveSnapshot(context.Background(), doc.ID, s.text.String(), s.history)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentClients(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for d := 0; d < 3; d++ {
+		doc := newTestDocument(t, "start typing")
+		clients := make([]*testClient, 4)
+		for i := range clients {
+			clients[i] = dialTestClient(t, srv, doc.ID, fmt.Sprint("user", i))
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var edits sync.WaitGroup
+			for i, c := range clients {
+				edits.Add(1)
+				go func(i int, c *testClient) {
+					defer edits.Done()
+					r := rand.New(rand.NewSource(int64(i)))
+					for j := 0; j < 50; j++ {
+						c.edit(r)
+					}
+				}(i, c)
+			}
+			edits.Wait()
+			// let the last operations reach everyone
+			time.Sleep(200 * time.Millisecond)
+
+			want := clients[0].current()
+			for i, c := range clients {
+				c.mu.Lock()
+				if c.text != want {
+					t.Errorf("client %v has %q, client 0 has %q", i, c.text, want)
+				}
+				c.mu.Unlock()
+				c.conn.Close()
+			}
+			if text := waitText(doc.ID, want); text != want {
+				t.Errorf("server has %q, clients have %q", text, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestHubChurn(t *testing.T) {
+	docs := []Document{newTestDocument(t, "a"), newTestDocument(t, "b")}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				s := sessions.acquire(docs[(i+j)%len(docs)])
+				s.do(func() {
+					if s.doc.ID != docs[(i+j)%len(docs)].ID {
+						t.Error("wrong session")
+					}
+				})
+				sessions.release(s)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	sessions.mu.Lock()
+	defer sessions.mu.Unlock()
+	if len(sessions.sessions) != 0 || len(sessions.refs) != 0 {
+		t.Errorf("%v sessions still open", len(sessions.sessions))
+	}
+}
+
+func TestSessionRestart(t *testing.T) {
+	doc := newTestDocument(t, "a")
+	old := sessions.acquire(doc)
+	old.do(func() {})
+	sessions.release(old)
+	// the new session starts while the old one may still hold the lease
+	s := sessions.acquire(doc)
+	defer sessions.release(s)
+	<-old.stopped
+
+	leader := func() bool {
+		var leader bool
+		s.do(func() { leader = s.leader })
+		return leader
+	}
+	// the old session resigns, so the new one takes over
+	if !waitFor(func() bool { return leader() }) {
+		t.Fatal("the new session did not become the sequencer")
+	}
+	if holder, _ := redisServer.Get(leaseKey(doc.ID)); holder != s.token {
+		t.Errorf("the lease is held by %q, not by the new session", holder)
+	}
+}
+
+func TestDeleteDocument(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "text")
+
+	c := dialTestClient(t, srv, doc.ID, "user")
+	c.edit(rand.New(rand.NewSource(1)))
+	// the read loop of the client fails the test on errors
+	c.conn.Close()
+
+	conn := dial(t, srv, doc.ID, "other")
+	defer conn.Close()
+	// the document is loaded once Init arrives
+	readEvent(t, conn)
+
+	if code, _ := request(t, srv, http.MethodDelete, "/api/v1/documents/"+doc.ID, "user", ""); code != http.StatusOK {
+		t.Fatalf("got status %v", code)
+	}
+
+	var code api_pb.ErrorCode
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		var ev api_pb.Event
+		if err := decoder.Unmarshal(bytes.NewReader(data), &ev); err == nil && ev.Type == api_pb.Event_ERROR {
+			var e api_pb.Error
+			decoder.Unmarshal(bytes.NewReader(ev.Event), &e)
+			code = e.Code
+		}
+	}
+	if code != api_pb.ErrorCode_DOCUMENT_DELETED {
+		t.Errorf("got error %v before the connection closed", code)
+	}
+
+	for i := 0; i < 100; i++ {
+		sessions.mu.Lock()
+		_, open := sessions.sessions[doc.ID]
+		sessions.mu.Unlock()
+		if !open {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	for _, key := range []string{"documents.", "texts.", "ops.", "snapshots."} {
+		if redisServer.Exists(key + doc.ID) {
+			t.Errorf("%v%v still exists", key, doc.ID)
+		}
+	}
+}
+
+func TestV1Revisions(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	b := dial(t, srv, doc.ID, "b")
+	defer b.Close()
+	readEvent(t, b)
+	a := dialTestClient(t, srv, doc.ID, "a")
+	defer a.conn.Close()
+
+	// v1 clients may still send batches
+	a.write(api_pb.Event_OPERATION_BATCH, &api_pb.OperationBatch{
+		Operations: []*api_pb.Operation{
+			{Type: api_pb.OpType_INSERT, Index: 0, Text: "x"},
+			{Type: api_pb.OpType_INSERT, Index: 4, Text: "y"},
+		},
+	})
+	a.wait()
+
+	// but get the operations of others one by one
+	var texts []string
+	for len(texts) < 2 {
+		ev := readEvent(t, b)
+		switch ev.Type {
+		case api_pb.Event_OPERATION:
+			var op api_pb.Operation
+			decoder.Unmarshal(bytes.NewReader(ev.Event), &op)
+			texts = append(texts, op.Text)
+		case api_pb.Event_OPERATION_BATCH, api_pb.Event_ERROR:
+			t.Fatalf("got %v", ev.Type)
+		}
+	}
+	if texts[0] != "x" || texts[1] != "y" {
+		t.Errorf("got operations inserting %q", texts)
+	}
+}
+
+func TestUndoWithoutPayload(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	conn := dial(t, srv, doc.ID, "user")
+	defer conn.Close()
+	readEvent(t, conn)
+
+	for _, data := range []string{`{"type":"UNDO"}`, `{"type":"REDO"}`} {
+		conn.WriteMessage(websocket.TextMessage, []byte(data))
+		ev := readEvent(t, conn)
+		var e api_pb.Error
+		decoder.Unmarshal(bytes.NewReader(ev.Event), &e)
+		if ev.Type != api_pb.Event_ERROR || e.Code != api_pb.ErrorCode_NOTHING_TO_UNDO {
+			t.Errorf("%s: got %v %v, want NOTHING_TO_UNDO", data, ev.Type, e.Code)
+		}
+	}
+}
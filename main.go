@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func main() {
 	r := gin.Default()
+	r.GET("/debug/vars", gin.WrapH(expvar.Handler()))
 
 	v1 := r.Group("/api/v1")
 	v1.POST("/auth", handleAuth)
@@ -15,9 +26,45 @@ func main() {
 	v1.POST("/documents/create", handleCreateDocument)
 	v1.GET("/documents/:id", handleSocket)
 	v1.DELETE("/documents/:id", handleDeleteDocument)
+	v1.GET("/documents/:id/history", handleGetHistory)
+	v1.GET("/documents/:id/versions/:v", handleGetVersion)
+	v1.POST("/documents/:id/restore", handleRestoreDocument)
+	v1.POST("/documents/:id/sync", handleSyncDocument)
 
-	err := r.Run("0.0.0.0:8080")
+	srv := &http.Server{
+		Addr:    "0.0.0.0:8080",
+		Handler: r,
+	}
+	go func() {
+		err := srv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("could not start server")
+		}
+	}()
+
+	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%v", grpcPort))
 	if err != nil {
-		log.Fatal().Err(err).Msg("could not start server")
+		log.Fatal().Err(err).Msg("could not listen for gRPC")
+	}
+	grpcSrv := newGRPCServer()
+	go func() {
+		if err := grpcSrv.Serve(lis); err != nil {
+			log.Fatal().Err(err).Msg("could not start gRPC server")
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Info().Msg("shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("error shutting down server")
 	}
+	// Edit streams last as long as their clients, so they are cut off
+	grpcSrv.Stop()
+	// open documents are flushed as their sessions stop
+	sessions.shutdown()
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/ssau-fiit/cloudocs-api/database"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Every revision applied to a document is stored as an entry of the ops.<id>
+// stream. The entry id is <version>-0, so ranges of versions map directly to
+// stream ranges.
+
+func opsKey(docID string) string {
+	return fmt.Sprintf("ops.%v", docID)
+}
+
+// saveRevision appends rev to the operation log of the document. It returns
+// errDocumentDeleted if the document no longer exists.
+func saveRevision(ctx context.Context, docID string, rev *ot.Revision) error {
+	ops, err := json.Marshal(rev.Ops)
+	if err != nil {
+		return err
+	}
+	opsUTF16, err := json.Marshal(rev.OpsUTF16)
+	if err != nil {
+		return err
+	}
+
+	return appendEntry(ctx, docID, opsKey(docID), fmt.Sprintf("%v-0", rev.Version),
+		"user", rev.UserID,
+		"connection", rev.ConnectionID,
+		"id", rev.ID,
+		"reverts", rev.Reverts,
+		"time", rev.Time.UnixMilli(),
+		"ops", ops,
+		"ops_utf16", opsUTF16,
+		"checksum", rev.Checksum,
+	)
+}
+
+// loadHistory reads every revision still kept in the log of the document. An
+// empty log gives an empty history starting at base.
+func loadHistory(ctx context.Context, docID string, base int32) (*ot.History, error) {
+	msgs, err := database.Database().XRange(ctx, opsKey(docID), "-", "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var history *ot.History
+	for _, msg := range msgs {
+		rev, err := parseRevision(msg)
+		if err != nil {
+			return nil, fmt.Errorf("revision %v: %w", msg.ID, err)
+		}
+		if history == nil {
+			history = ot.NewHistory(rev.Version - 1)
+		}
+		if err := history.Append(rev); err != nil {
+			return nil, fmt.Errorf("revision %v: %w", msg.ID, err)
+		}
+	}
+	if history == nil {
+		history = ot.NewHistory(base)
+	}
+	return history, nil
+}
+
+func parseRevision(msg redis.XMessage) (*ot.Revision, error) {
+	version, _, _ := strings.Cut(msg.ID, "-")
+	v, err := strconv.ParseInt(version, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	millis, err := strconv.ParseInt(field(msg, "time"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	// revisions stored before checksums were added have none
+	checksum, _ := strconv.ParseUint(field(msg, "checksum"), 10, 32)
+	reverts, _ := strconv.ParseInt(field(msg, "reverts"), 10, 32)
+
+	rev := &ot.Revision{
+		Version:      int32(v),
+		UserID:       field(msg, "user"),
+		ConnectionID: field(msg, "connection"),
+		ID:           field(msg, "id"),
+		Reverts:      int32(reverts),
+		Time:         time.UnixMilli(millis),
+		Checksum:     uint32(checksum),
+	}
+	if err := json.Unmarshal([]byte(field(msg, "ops")), &rev.Ops); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(field(msg, "ops_utf16")), &rev.OpsUTF16); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+func field(msg redis.XMessage, name string) string {
+	v, _ := msg.Values[name].(string)
+	return v
+}
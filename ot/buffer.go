@@ -0,0 +1,371 @@
+package ot
+
+import (
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// maxLeaf is the most bytes of text kept in a single leaf of a Buffer.
+const maxLeaf = 1024
+
+// Buffer is a document text stored as a rope: a balanced tree of short text
+// chunks, each node caching the length of its text in code points and UTF-16
+// code units. Nodes also cache their checksum once it is asked for, so the
+// checksum after an edit only combines the checksums along the edited path. Edits and position conversions take time logarithmic in the
+// length of the text instead of copying all of it.
+//
+// Nodes are never modified once built, so a Buffer can be cloned in constant
+// time and the clone edited without affecting the original.
+type Buffer struct {
+	root *node
+}
+
+type node struct {
+	left, right *node
+	// text is only set on leaves
+	text   string
+	bytes  int
+	runes  int32
+	utf16  int32
+	height int8
+	// crc holds the checksum of the text with bit 32 set, once computed
+	crc atomic.Uint64
+}
+
+// NewBuffer returns a buffer holding text.
+func NewBuffer(text string) *Buffer {
+	return &Buffer{root: build(text)}
+}
+
+// Clone returns a copy of the buffer.
+func (b *Buffer) Clone() *Buffer {
+	return &Buffer{root: b.root}
+}
+
+// Len returns the length of the text counted in unit.
+func (b *Buffer) Len(unit api_pb.PositionUnit) int32 {
+	if b.root == nil {
+		return 0
+	}
+	if unit == api_pb.PositionUnit_UTF16 {
+		return b.root.utf16
+	}
+	return b.root.runes
+}
+
+// String returns the whole text.
+func (b *Buffer) String() string {
+	if b.root == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.Grow(b.root.bytes)
+	b.root.each(func(s string) {
+		sb.WriteString(s)
+	})
+	return sb.String()
+}
+
+// Checksum returns the same checksum as Checksum(b.String()) without building
+// the string.
+func (b *Buffer) Checksum() uint32 {
+	return b.root.checksum()
+}
+
+// Insert inserts text at the code point index.
+func (b *Buffer) Insert(index int32, text string) {
+	l, r := split(b.root, index)
+	b.root = join(join(l, build(text)), r)
+}
+
+// Delete removes n code points starting at index and returns them.
+func (b *Buffer) Delete(index, n int32) string {
+	l, r := split(b.root, index)
+	deleted, r := split(r, n)
+	b.root = join(l, r)
+	return (&Buffer{root: deleted}).String()
+}
+
+// ToCodePoints converts a position counted in unit into a code point index.
+func (b *Buffer) ToCodePoints(pos int32, unit api_pb.PositionUnit) (int32, error) {
+	if pos < 0 || pos > b.Len(unit) {
+		return 0, ErrOutOfRange
+	}
+	if unit == api_pb.PositionUnit_CODE_POINTS {
+		return pos, nil
+	}
+
+	var index int32
+	n := b.root
+	for n != nil && n.left != nil {
+		if pos < n.left.utf16 {
+			n = n.left
+			continue
+		}
+		pos -= n.left.utf16
+		index += n.left.runes
+		n = n.right
+	}
+	if n == nil {
+		return 0, nil
+	}
+
+	var units int32
+	for _, r := range n.text {
+		switch {
+		case units == pos:
+			return index, nil
+		case units > pos:
+			return 0, ErrSplitCharacter
+		}
+		units += int32(utf16Width(r))
+		index++
+	}
+	if units > pos {
+		return 0, ErrSplitCharacter
+	}
+	return index, nil
+}
+
+// FromCodePoints converts a code point index into a position counted in unit.
+func (b *Buffer) FromCodePoints(index int32, unit api_pb.PositionUnit) int32 {
+	if unit == api_pb.PositionUnit_CODE_POINTS {
+		return index
+	}
+
+	var pos int32
+	n := b.root
+	for n != nil && n.left != nil {
+		if index < n.left.runes {
+			n = n.left
+			continue
+		}
+		index -= n.left.runes
+		pos += n.left.utf16
+		n = n.right
+	}
+	if n == nil {
+		return 0
+	}
+
+	for _, r := range n.text {
+		if index <= 0 {
+			break
+		}
+		pos += int32(utf16Width(r))
+		index--
+	}
+	return pos
+}
+
+// Apply applies ops, with positions counted in unit, to the buffer in order,
+// like the Apply function. The buffer is left unchanged if any of them fails.
+func (b *Buffer) Apply(ops []*api_pb.Operation, unit api_pb.PositionUnit) (Change, error) {
+	root := b.root
+	var change Change
+
+	for _, op := range ops {
+		index, err := b.ToCodePoints(op.Index, unit)
+		if err != nil {
+			b.root = root
+			return Change{}, err
+		}
+		res := resized(op, index, op.Len)
+
+		switch op.Type {
+		case api_pb.OpType_INSERT:
+			res.Len = int32(utf8.RuneCountInString(op.Text))
+			b.Insert(index, op.Text)
+		case api_pb.OpType_DELETE:
+			end, err := b.ToCodePoints(b.FromCodePoints(index, unit)+op.Len, unit)
+			if err == nil && end < index {
+				err = ErrOutOfRange
+			}
+			if err != nil {
+				b.root = root
+				return Change{}, err
+			}
+			res.Len = end - index
+			res.Text = b.Delete(index, res.Len)
+		}
+
+		res16 := resized(res, b.FromCodePoints(index, api_pb.PositionUnit_UTF16), Length(res.Text, api_pb.PositionUnit_UTF16))
+		res16.Text = res.Text
+		change.Ops = append(change.Ops, res)
+		change.OpsUTF16 = append(change.OpsUTF16, res16)
+	}
+	return change, nil
+}
+
+// each calls f with the text of every leaf in order.
+func (n *node) each(f func(string)) {
+	switch {
+	case n == nil:
+	case n.left == nil:
+		f(n.text)
+	default:
+		n.left.each(f)
+		n.right.each(f)
+	}
+}
+
+// checksum returns the checksum of the text under n, computing it from the
+// checksums of the children the first time.
+func (n *node) checksum() uint32 {
+	if n == nil {
+		return 0
+	}
+	if crc := n.crc.Load(); crc != 0 {
+		return uint32(crc)
+	}
+	var crc uint32
+	if n.left == nil {
+		crc = Checksum(n.text)
+	} else {
+		crc = combineCRC(n.left.checksum(), n.right.checksum(), n.right.bytes)
+	}
+	n.crc.Store(1<<32 | uint64(crc))
+	return crc
+}
+
+func (n *node) h() int8 {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func leaf(text string) *node {
+	if text == "" {
+		return nil
+	}
+	n := &node{text: text, bytes: len(text), height: 1}
+	for _, r := range text {
+		n.runes++
+		n.utf16 += int32(utf16Width(r))
+	}
+	return n
+}
+
+func branch(l, r *node) *node {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	}
+	height := l.height
+	if r.height > height {
+		height = r.height
+	}
+	return &node{
+		left:   l,
+		right:  r,
+		bytes:  l.bytes + r.bytes,
+		runes:  l.runes + r.runes,
+		utf16:  l.utf16 + r.utf16,
+		height: height + 1,
+	}
+}
+
+// build returns a balanced tree holding text.
+func build(text string) *node {
+	var leaves []*node
+	for len(text) > maxLeaf {
+		cut := maxLeaf
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		leaves = append(leaves, leaf(text[:cut]))
+		text = text[cut:]
+	}
+	leaves = append(leaves, leaf(text))
+	return buildLeaves(leaves)
+}
+
+func buildLeaves(leaves []*node) *node {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	mid := len(leaves) / 2
+	return branch(buildLeaves(leaves[:mid]), buildLeaves(leaves[mid:]))
+}
+
+// join concatenates two trees, keeping the result balanced. Small neighbouring
+// leaves are merged so typing doesn't leave behind a leaf per character.
+func join(l, r *node) *node {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.left == nil && r.left == nil && l.bytes+r.bytes <= maxLeaf:
+		return leaf(l.text + r.text)
+	case l.height > r.height+1:
+		return balance(branch(l.left, join(l.right, r)))
+	case r.height > l.height+1:
+		return balance(branch(join(l, r.left), r.right))
+	}
+	return branch(l, r)
+}
+
+// split returns the trees holding the first index code points of n and the
+// rest.
+func split(n *node, index int32) (*node, *node) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case index <= 0:
+		return nil, n
+	case index >= n.runes:
+		return n, nil
+	case n.left == nil:
+		at := 0
+		for i := range n.text {
+			if index == 0 {
+				at = i
+				break
+			}
+			index--
+		}
+		return leaf(n.text[:at]), leaf(n.text[at:])
+	case index < n.left.runes:
+		l, r := split(n.left, index)
+		return l, join(r, n.right)
+	case index == n.left.runes:
+		return n.left, n.right
+	default:
+		l, r := split(n.right, index-n.left.runes)
+		return join(n.left, l), r
+	}
+}
+
+func balance(n *node) *node {
+	switch {
+	case n.left.h() > n.right.h()+1:
+		l := n.left
+		if l.left.h() < l.right.h() {
+			l = rotateLeft(l)
+		}
+		return rotateRight(branch(l, n.right))
+	case n.right.h() > n.left.h()+1:
+		r := n.right
+		if r.right.h() < r.left.h() {
+			r = rotateRight(r)
+		}
+		return rotateLeft(branch(n.left, r))
+	}
+	return n
+}
+
+func rotateRight(n *node) *node {
+	l := n.left
+	return branch(l.left, branch(l.right, n.right))
+}
+
+func rotateLeft(n *node) *node {
+	r := n.right
+	return branch(branch(n.left, r.left), r.right)
+}
@@ -0,0 +1,193 @@
+package ot
+
+import (
+	"errors"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var pieces = []string{"a", "bc", "é", "😀", "x😀y", "é́"}
+
+func randomText(r *rand.Rand, n int) string {
+	var sb strings.Builder
+	for i := 0; i < n; i++ {
+		sb.WriteString(pieces[r.Intn(len(pieces))])
+	}
+	return sb.String()
+}
+
+// checkNode checks the cached lengths and the balance of the tree under n and
+// returns its height.
+func checkNode(t *testing.T, n *node) int8 {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	if n.left == nil {
+		if n.right != nil || n.text == "" || len(n.text) > maxLeaf {
+			t.Fatalf("bad leaf of %v bytes", len(n.text))
+		}
+		if n.bytes != len(n.text) || n.runes != Length(n.text, api_pb.PositionUnit_CODE_POINTS) ||
+			n.utf16 != Length(n.text, api_pb.PositionUnit_UTF16) || n.height != 1 {
+			t.Fatalf("leaf %q caches %v bytes, %v runes, %v units", n.text, n.bytes, n.runes, n.utf16)
+		}
+		return 1
+	}
+
+	if n.right == nil || n.text != "" {
+		t.Fatal("branch with a single child or text")
+	}
+	lh, rh := checkNode(t, n.left), checkNode(t, n.right)
+	if lh-rh > 1 || rh-lh > 1 {
+		t.Fatalf("unbalanced branch of heights %v and %v", lh, rh)
+	}
+	height := lh
+	if rh > height {
+		height = rh
+	}
+	if n.height != height+1 || n.bytes != n.left.bytes+n.right.bytes ||
+		n.runes != n.left.runes+n.right.runes || n.utf16 != n.left.utf16+n.right.utf16 {
+		t.Fatal("branch caches don't add up")
+	}
+	return n.height
+}
+
+// checkBuffer checks b holds the same text as want.
+func checkBuffer(t *testing.T, b *Buffer, want []rune) {
+	t.Helper()
+	checkNode(t, b.root)
+	text := string(want)
+	if got := b.String(); got != text {
+		t.Fatalf("buffer holds %q, want %q", got, text)
+	}
+	if b.Len(api_pb.PositionUnit_CODE_POINTS) != int32(len(want)) ||
+		b.Len(api_pb.PositionUnit_UTF16) != Length(text, api_pb.PositionUnit_UTF16) {
+		t.Fatalf("buffer of %q has wrong lengths", text)
+	}
+	if b.Checksum() != Checksum(text) {
+		t.Fatalf("checksum of %q differs from the checksum of the buffer", text)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		want := []rune(randomText(r, r.Intn(3000)))
+		b := NewBuffer(string(want))
+		checkBuffer(t, b, want)
+
+		for j := 0; j < 300; j++ {
+			before, clone := string(want), b.Clone()
+			index := r.Intn(len(want) + 1)
+			if len(want) > 0 && r.Intn(2) == 0 {
+				n := r.Intn(len(want) - index + 1)
+				if r.Intn(10) > 0 && n > 10 {
+					n = r.Intn(10)
+				}
+				deleted := b.Delete(int32(index), int32(n))
+				if deleted != string(want[index:index+n]) {
+					t.Fatalf("deleted %q, want %q", deleted, string(want[index:index+n]))
+				}
+				want = append(want[:index:index], want[index+n:]...)
+			} else {
+				// mostly typing, sometimes a paste spanning several leaves
+				text := randomText(r, 1+r.Intn(3))
+				if r.Intn(20) == 0 {
+					text = randomText(r, r.Intn(2000))
+				}
+				b.Insert(int32(index), text)
+				want = append(want[:index:index], append([]rune(text), want[index:]...)...)
+			}
+			checkBuffer(t, b, want)
+			checkBuffer(t, clone, []rune(before))
+		}
+	}
+}
+
+func TestBufferPositions(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	text := randomText(r, 2000)
+	b := NewBuffer(text)
+	if b.root.height < 3 {
+		t.Fatal("text fits in too few leaves")
+	}
+
+	// units[i] is the UTF-16 position of the code point i
+	var units []int32
+	var pos int32
+	for _, c := range text {
+		units = append(units, pos)
+		pos += int32(utf16Width(c))
+	}
+	units = append(units, pos)
+
+	index := 0
+	for pos := int32(0); pos <= b.Len(api_pb.PositionUnit_UTF16); pos++ {
+		got, err := b.ToCodePoints(pos, api_pb.PositionUnit_UTF16)
+		if units[index] < pos {
+			index++
+		}
+		if units[index] != pos {
+			if !errors.Is(err, ErrSplitCharacter) {
+				t.Fatalf("position %v splits a surrogate pair, got %v, %v", pos, got, err)
+			}
+			continue
+		}
+		if err != nil || got != int32(index) {
+			t.Fatalf("position %v is code point %v, got %v, %v", pos, index, got, err)
+		}
+		if back := b.FromCodePoints(int32(index), api_pb.PositionUnit_UTF16); back != pos {
+			t.Fatalf("code point %v is at position %v, got %v", index, pos, back)
+		}
+	}
+	if index != utf8.RuneCountInString(text) {
+		t.Fatalf("went through %v code points of %v", index, utf8.RuneCountInString(text))
+	}
+
+	for _, unit := range []api_pb.PositionUnit{api_pb.PositionUnit_CODE_POINTS, api_pb.PositionUnit_UTF16} {
+		for _, pos := range []int32{-1, b.Len(unit) + 1} {
+			if _, err := b.ToCodePoints(pos, unit); !errors.Is(err, ErrOutOfRange) {
+				t.Errorf("%v position %v is out of range, got %v", unit, pos, err)
+			}
+		}
+	}
+}
+
+func TestBufferEmpty(t *testing.T) {
+	b := NewBuffer("")
+	checkBuffer(t, b, nil)
+	if b.Checksum() != Checksum("") || b.Delete(0, 0) != "" {
+		t.Fatal("empty buffer")
+	}
+	b.Insert(0, "😀")
+	b.Delete(0, 1)
+	checkBuffer(t, b, nil)
+}
+
+func benchmarkBuffer(b *testing.B, edit func(buf *Buffer, index int32)) {
+	r := rand.New(rand.NewSource(3))
+	base := NewBuffer(randomText(r, 2_000_000))
+	buf := base.Clone()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if buf.Len(api_pb.PositionUnit_CODE_POINTS) < 1000 {
+			buf = base.Clone()
+		}
+		edit(buf, r.Int31n(buf.Len(api_pb.PositionUnit_CODE_POINTS)))
+	}
+}
+
+func BenchmarkBufferInsert(b *testing.B) {
+	benchmarkBuffer(b, func(buf *Buffer, index int32) {
+		buf.Insert(index, "x")
+	})
+}
+
+func BenchmarkBufferDelete(b *testing.B) {
+	benchmarkBuffer(b, func(buf *Buffer, index int32) {
+		buf.Delete(index, 1)
+	})
+}
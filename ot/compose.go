@@ -0,0 +1,97 @@
+package ot
+
+import (
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// Compose merges adjacent operations of a sequence, such as consecutive typed
+// characters or backspaces, so fewer of them have to be applied and stored.
+// Positions are counted in unit.
+func Compose(ops []*api_pb.Operation, unit api_pb.PositionUnit) []*api_pb.Operation {
+	res := make([]*api_pb.Operation, 0, len(ops))
+	for _, op := range ops {
+		if op.Len == 0 {
+			continue
+		}
+		if len(res) == 0 {
+			res = append(res, op)
+			continue
+		}
+
+		merged, ok := compose(res[len(res)-1], op, unit)
+		switch {
+		case !ok:
+			res = append(res, op)
+		case merged == nil:
+			res = res[:len(res)-1]
+		default:
+			res[len(res)-1] = merged
+		}
+	}
+	return res
+}
+
+// compose merges b, which applies after a, into a. A nil result means the two
+// cancel out.
+func compose(a, b *api_pb.Operation, unit api_pb.PositionUnit) (*api_pb.Operation, bool) {
+	aEnd, bEnd := a.Index+a.Len, b.Index+b.Len
+
+	switch {
+	case a.Type == api_pb.OpType_INSERT && b.Type == api_pb.OpType_INSERT:
+		if b.Index < a.Index || b.Index > aEnd {
+			return nil, false
+		}
+		at, ok := byteOffset(a.Text, b.Index-a.Index, unit)
+		if !ok {
+			return nil, false
+		}
+		res := resized(a, a.Index, a.Len+b.Len)
+		res.Text = a.Text[:at] + b.Text + a.Text[at:]
+		return res, true
+
+	case a.Type == api_pb.OpType_DELETE && b.Type == api_pb.OpType_DELETE:
+		switch {
+		case b.Index == a.Index:
+			return resized(a, a.Index, a.Len+b.Len), true
+		case bEnd == a.Index:
+			return resized(a, b.Index, a.Len+b.Len), true
+		}
+
+	case a.Type == api_pb.OpType_INSERT && b.Type == api_pb.OpType_DELETE:
+		if b.Index < a.Index || bEnd > aEnd {
+			return nil, false
+		}
+		from, ok1 := byteOffset(a.Text, b.Index-a.Index, unit)
+		to, ok2 := byteOffset(a.Text, bEnd-a.Index, unit)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		if a.Len == b.Len {
+			return nil, true
+		}
+		res := resized(a, a.Index, a.Len-b.Len)
+		res.Text = a.Text[:from] + a.Text[to:]
+		return res, true
+	}
+	return nil, false
+}
+
+// byteOffset returns the byte offset in s of a position counted in unit. It
+// fails if the position is inside a character.
+func byteOffset(s string, pos int32, unit api_pb.PositionUnit) (int, bool) {
+	var n int32
+	for i, r := range s {
+		if n == pos {
+			return i, true
+		}
+		if n > pos {
+			return 0, false
+		}
+		if unit == api_pb.PositionUnit_UTF16 {
+			n += int32(utf16Width(r))
+		} else {
+			n++
+		}
+	}
+	return len(s), n == pos
+}
@@ -0,0 +1,59 @@
+package ot
+
+// The checksum of a Buffer is combined from the CRC-32 of its leaves, so an
+// edit only recomputes it along the path to the root. Combining follows
+// crc32_combine of zlib: the CRC of A followed by B is the CRC of A shifted
+// over the length of B, which is a multiplication by x^(8*len(B)) modulo the
+// CRC polynomial, xored with the CRC of B.
+
+// crcPoly is the IEEE polynomial, reflected.
+const crcPoly = 0xedb88320
+
+// x2n holds x^(2^k) modulo the polynomial.
+var x2n [32]uint32
+
+func init() {
+	p := uint32(1) << 30 // x^1
+	x2n[0] = p
+	for k := 1; k < len(x2n); k++ {
+		p = multModP(p, p)
+		x2n[k] = p
+	}
+}
+
+// multModP returns a(x) multiplied by b(x) modulo the polynomial.
+func multModP(a, b uint32) uint32 {
+	var p uint32
+	for m := uint32(1) << 31; m != 0; m >>= 1 {
+		if a&m != 0 {
+			p ^= b
+			if a&(m-1) == 0 {
+				break
+			}
+		}
+		if b&1 != 0 {
+			b = b>>1 ^ crcPoly
+		} else {
+			b >>= 1
+		}
+	}
+	return p
+}
+
+// x8nModP returns x^(8n) modulo the polynomial.
+func x8nModP(n int) uint32 {
+	p := uint32(1) << 31 // x^0
+	for k := 3; n != 0; k++ {
+		if n&1 != 0 {
+			p = multModP(x2n[k&31], p)
+		}
+		n >>= 1
+	}
+	return p
+}
+
+// combineCRC returns the CRC-32 of the concatenation of two texts given their
+// CRCs and the length of the second one in bytes.
+func combineCRC(crc1, crc2 uint32, len2 int) uint32 {
+	return multModP(x8nModP(len2), crc1) ^ crc2
+}
@@ -0,0 +1,57 @@
+package ot
+
+import (
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// Diff returns the operations turning text from into text to. Only the part
+// between their common prefix and suffix is replaced. Positions are counted in
+// code points.
+func Diff(from, to string) []*api_pb.Operation {
+	a, b := []rune(from), []rune(to)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var res []*api_pb.Operation
+	if deleted := a[prefix : len(a)-suffix]; len(deleted) > 0 {
+		res = append(res, &api_pb.Operation{
+			Type:  api_pb.OpType_DELETE,
+			Index: int32(prefix),
+			Len:   int32(len(deleted)),
+			Text:  string(deleted),
+		})
+	}
+	if inserted := b[prefix : len(b)-suffix]; len(inserted) > 0 {
+		res = append(res, &api_pb.Operation{
+			Type:  api_pb.OpType_INSERT,
+			Index: int32(prefix),
+			Len:   int32(len(inserted)),
+			Text:  string(inserted),
+		})
+	}
+	return res
+}
+
+// Invert returns the operations undoing ops. Deletions need their Text set, as
+// done by Apply.
+func Invert(ops []*api_pb.Operation) []*api_pb.Operation {
+	res := make([]*api_pb.Operation, 0, len(ops))
+	for i := len(ops) - 1; i >= 0; i-- {
+		inv := resized(ops[i], ops[i].Index, ops[i].Len)
+		inv.Text = ops[i].Text
+		if ops[i].Type == api_pb.OpType_INSERT {
+			inv.Type = api_pb.OpType_DELETE
+		} else {
+			inv.Type = api_pb.OpType_INSERT
+		}
+		res = append(res, inv)
+	}
+	return res
+}
@@ -0,0 +1,247 @@
+package ot
+
+import (
+	"errors"
+	"fmt"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"hash/crc32"
+	"time"
+)
+
+var (
+	ErrUnknownVersion = errors.New("ot: operation is based on an unknown version")
+	ErrCompacted      = errors.New("ot: version is older than the kept history")
+	ErrOutOfRange     = errors.New("ot: operation is out of the document range")
+	ErrSplitCharacter = errors.New("ot: operation position splits a character")
+	ErrInvalid        = errors.New("ot: invalid operation")
+)
+
+// Change holds the same operations with positions counted in code points and
+// in UTF-16 code units.
+type Change struct {
+	Ops      []*api_pb.Operation
+	OpsUTF16 []*api_pb.Operation
+}
+
+// In returns the operations with positions counted in unit.
+func (c Change) In(unit api_pb.PositionUnit) []*api_pb.Operation {
+	if unit == api_pb.PositionUnit_UTF16 {
+		return c.OpsUTF16
+	}
+	return c.Ops
+}
+
+// Revision is a set of operations applied to a document at a single version.
+type Revision struct {
+	Change
+	Version int32
+	UserID  string
+	// ConnectionID is the connection the revision was made from.
+	ConnectionID string
+	// ID is the id the client gave the operation, if any.
+	ID string
+	// Reverts is the version of the revision this one undoes or redoes, if
+	// any.
+	Reverts int32
+	Time    time.Time
+	// Checksum is the checksum of the document text after the revision.
+	Checksum uint32
+}
+
+// RevisionID identifies a revision by the client operation it was made from.
+// Clients pick ids on their own, so they are only unique per user.
+type RevisionID struct {
+	UserID string
+	ID     string
+}
+
+// History is the ordered list of revisions applied to a document after its base
+// version. The server is the only one assigning versions, so revision i always
+// has version base+i+1.
+type History struct {
+	base      int32
+	revisions []*Revision
+	ids       map[RevisionID]*Revision
+}
+
+// NewHistory returns an empty history that continues from version base.
+func NewHistory(base int32) *History {
+	return &History{base: base}
+}
+
+// Base returns the oldest version the history can transform from.
+func (h *History) Base() int32 {
+	return h.base
+}
+
+// Version returns the version of the latest revision, or the base version for
+// an empty history.
+func (h *History) Version() int32 {
+	return h.base + int32(len(h.revisions))
+}
+
+// Since returns the revisions applied after version.
+func (h *History) Since(version int32) ([]*Revision, error) {
+	switch {
+	case version > h.Version():
+		return nil, ErrUnknownVersion
+	case version < h.base:
+		return nil, ErrCompacted
+	}
+	return h.revisions[version-h.base:], nil
+}
+
+// Compact forgets the revisions up to and including version.
+func (h *History) Compact(version int32) {
+	if version <= h.base {
+		return
+	}
+	if version > h.Version() {
+		version = h.Version()
+	}
+
+	n := version - h.base
+	for _, rev := range h.revisions[:n] {
+		delete(h.ids, RevisionID{rev.UserID, rev.ID})
+	}
+	h.revisions = append([]*Revision(nil), h.revisions[n:]...)
+	h.base = version
+}
+
+// At returns the revision with the given version, or nil if it is not kept.
+func (h *History) At(version int32) *Revision {
+	if version <= h.base || version > h.Version() {
+		return nil
+	}
+	return h.revisions[version-h.base-1]
+}
+
+// Find returns the revision made from the client operation of userID with the
+// given id, or nil if there is none.
+func (h *History) Find(userID, id string) *Revision {
+	if id == "" {
+		return nil
+	}
+	return h.ids[RevisionID{userID, id}]
+}
+
+// Transform rewrites ops, which were made one after another against version, so
+// that they apply to the latest version of the document. Positions stay counted
+// in unit.
+func (h *History) Transform(ops []*api_pb.Operation, version int32, unit api_pb.PositionUnit) ([]*api_pb.Operation, error) {
+	revs, err := h.Since(version)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*api_pb.Operation, len(ops))
+	for i, op := range ops {
+		if err := validate(op); err != nil {
+			return nil, err
+		}
+		res[i] = normalized(op, unit)
+	}
+	for _, rev := range revs {
+		res, _ = Transform(res, rev.In(unit))
+	}
+	return res, nil
+}
+
+// TransformPosition moves pos, which refers to version, to the latest version
+// of the document. Positions stay counted in unit.
+func (h *History) TransformPosition(pos, version int32, unit api_pb.PositionUnit, owner string) (int32, error) {
+	revs, err := h.Since(version)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rev := range revs {
+		pos = TransformPosition(pos, rev.In(unit), owner)
+	}
+	return pos, nil
+}
+
+// Next returns change, which left the document text with the given checksum,
+// as the revision following the latest one, made by userID over connectionID
+// from the client operation id, with every operation stamped with those. It is
+// not recorded until passed to Append.
+func (h *History) Next(userID, connectionID, id string, change Change, checksum uint32) *Revision {
+	rev := &Revision{
+		Change:       change,
+		Version:      h.Version() + 1,
+		UserID:       userID,
+		ConnectionID: connectionID,
+		ID:           id,
+		Time:         time.Now(),
+		Checksum:     checksum,
+	}
+	for i := range change.Ops {
+		for _, op := range []*api_pb.Operation{change.Ops[i], change.OpsUTF16[i]} {
+			op.UserID = userID
+			op.ConnectionId = connectionID
+			op.Id = id
+			op.Version = rev.Version
+			op.Checksum = rev.Checksum
+		}
+	}
+	return rev
+}
+
+// Append records rev, which has to follow the latest revision.
+func (h *History) Append(rev *Revision) error {
+	if rev.Version != h.Version()+1 {
+		return ErrUnknownVersion
+	}
+	h.revisions = append(h.revisions, rev)
+
+	if rev.ID != "" {
+		if h.ids == nil {
+			h.ids = make(map[RevisionID]*Revision)
+		}
+		h.ids[RevisionID{rev.UserID, rev.ID}] = rev
+	}
+	return nil
+}
+
+// Checksum returns the CRC-32 of text, which clients compare against to detect
+// that their copy of the document diverged.
+func Checksum(text string) uint32 {
+	return crc32.ChecksumIEEE([]byte(text))
+}
+
+// Apply applies ops, with positions counted in unit, to text in order. The
+// returned change has deletions' Text set to the text they removed.
+func Apply(text string, ops []*api_pb.Operation, unit api_pb.PositionUnit) (string, Change, error) {
+	buf := NewBuffer(text)
+	change, err := buf.Apply(ops, unit)
+	if err != nil {
+		return "", Change{}, err
+	}
+	return buf.String(), change, nil
+}
+
+func validate(op *api_pb.Operation) error {
+	switch {
+	case op.Type != api_pb.OpType_INSERT && op.Type != api_pb.OpType_DELETE:
+		return fmt.Errorf("%w: unknown type %v", ErrInvalid, op.Type)
+	case op.Index < 0:
+		return fmt.Errorf("%w: negative index", ErrInvalid)
+	case op.Type == api_pb.OpType_DELETE && op.Len < 0:
+		return fmt.Errorf("%w: negative length", ErrInvalid)
+	case op.Type == api_pb.OpType_DELETE && op.Len == 0:
+		return fmt.Errorf("%w: empty delete", ErrInvalid)
+	case op.Type == api_pb.OpType_INSERT && op.Text == "":
+		return fmt.Errorf("%w: empty insert", ErrInvalid)
+	}
+	return nil
+}
+
+// normalized returns a copy of op with the length of inserts taken from their
+// text, so clients can't make the server skip over part of it.
+func normalized(op *api_pb.Operation, unit api_pb.PositionUnit) *api_pb.Operation {
+	res := resized(op, op.Index, op.Len)
+	if res.Type == api_pb.OpType_INSERT {
+		res.Len = Length(res.Text, unit)
+	}
+	return res
+}
@@ -0,0 +1,56 @@
+package ot
+
+import (
+	"errors"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"testing"
+)
+
+func TestHistoryTransformValidates(t *testing.T) {
+	tests := []struct {
+		name string
+		op   *api_pb.Operation
+	}{
+		{"unknown type", &api_pb.Operation{Type: 7, Index: 0, Len: 1}},
+		{"negative index", ins(-1, "x")},
+		{"negative length", del(0, -1)},
+		{"empty delete", del(1, 0)},
+		{"empty insert", ins(1, "")},
+	}
+	h := NewHistory(0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h.Transform(ops(tt.op), 0, api_pb.PositionUnit_CODE_POINTS)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("got %v, want ErrInvalid", err)
+			}
+		})
+	}
+
+	if _, err := h.Transform(ops(ins(0, "x"), del(0, 1)), 0, api_pb.PositionUnit_CODE_POINTS); err != nil {
+		t.Errorf("valid operations rejected: %v", err)
+	}
+}
+
+func TestHistoryNext(t *testing.T) {
+	h := NewHistory(0)
+	_, change, err := Apply("abc", ops(ins(1, "😀"), del(3, 1)), api_pb.PositionUnit_CODE_POINTS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rev := h.Next("user", "conn", "batch", change, 42)
+	if err := h.Append(rev); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, unit := range []api_pb.PositionUnit{api_pb.PositionUnit_CODE_POINTS, api_pb.PositionUnit_UTF16} {
+		for _, op := range rev.In(unit) {
+			if op.UserID != "user" || op.ConnectionId != "conn" || op.Id != "batch" || op.Version != 1 || op.Checksum != 42 {
+				t.Errorf("%v: operation %v not stamped with its revision", unit, op)
+			}
+		}
+	}
+	if h.Find("user", "batch") != rev || h.Find("other", "batch") != nil {
+		t.Error("revision not found by the user that made it")
+	}
+}
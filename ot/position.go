@@ -0,0 +1,60 @@
+package ot
+
+import (
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"unicode/utf8"
+)
+
+// Length returns the length of s counted in unit.
+func Length(s string, unit api_pb.PositionUnit) int32 {
+	if unit == api_pb.PositionUnit_UTF16 {
+		n := 0
+		for _, r := range s {
+			n += utf16Width(r)
+		}
+		return int32(n)
+	}
+	return int32(utf8.RuneCountInString(s))
+}
+
+// ToCodePoints converts a position in text counted in unit into a code point
+// index.
+func ToCodePoints(text string, pos int32, unit api_pb.PositionUnit) (int32, error) {
+	return NewBuffer(text).ToCodePoints(pos, unit)
+}
+
+// FromCodePoints converts a code point index in text into a position counted
+// in unit.
+func FromCodePoints(text string, index int32, unit api_pb.PositionUnit) int32 {
+	return NewBuffer(text).FromCodePoints(index, unit)
+}
+
+// TransformPosition moves pos past ops. A position right where text gets
+// inserted only moves if the insert was made over the connection owner, so a
+// caret follows its own typing but not others', even other tabs of the same
+// user.
+func TransformPosition(pos int32, ops []*api_pb.Operation, owner string) int32 {
+	for _, op := range ops {
+		switch op.Type {
+		case api_pb.OpType_INSERT:
+			if op.Index < pos || op.Index == pos && op.ConnectionId == owner {
+				pos += op.Len
+			}
+		case api_pb.OpType_DELETE:
+			switch {
+			case op.Index+op.Len <= pos:
+				pos -= op.Len
+			case op.Index < pos:
+				pos = op.Index
+			}
+		}
+	}
+	return pos
+}
+
+func utf16Width(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
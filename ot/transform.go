@@ -0,0 +1,130 @@
+package ot
+
+import (
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// Transform takes two sequences of operations made concurrently against the same
+// document state and returns a', which applies a after b, and b', which applies
+// b after a. When both sides insert at the same position, b ends up first.
+func Transform(a, b []*api_pb.Operation) ([]*api_pb.Operation, []*api_pb.Operation) {
+	if len(a) == 0 || len(b) == 0 {
+		return a, b
+	}
+	if len(a) == 1 && len(b) == 1 {
+		return transformOp(a[0], b[0])
+	}
+	if len(a) > 1 {
+		a1, b1 := Transform(a[:1], b)
+		a2, b2 := Transform(a[1:], b1)
+		return append(a1, a2...), b2
+	}
+	a1, b1 := Transform(a, b[:1])
+	a2, b2 := Transform(a1, b[1:])
+	return a2, append(b1, b2...)
+}
+
+func transformOp(a, b *api_pb.Operation) ([]*api_pb.Operation, []*api_pb.Operation) {
+	switch {
+	case a.Type == api_pb.OpType_INSERT && b.Type == api_pb.OpType_INSERT:
+		if b.Index <= a.Index {
+			return ops(moved(a, a.Index+b.Len)), ops(b)
+		}
+		return ops(a), ops(moved(b, b.Index+a.Len))
+
+	case a.Type == api_pb.OpType_INSERT && b.Type == api_pb.OpType_DELETE:
+		bp := transformDelete(b, a)
+		return ops(transformInsert(a, b)), bp
+
+	case a.Type == api_pb.OpType_DELETE && b.Type == api_pb.OpType_INSERT:
+		ap := transformDelete(a, b)
+		return ap, ops(transformInsert(b, a))
+
+	default:
+		return deleteAfterDelete(a, b), deleteAfterDelete(b, a)
+	}
+}
+
+// transformInsert moves insert ins past the deletion del.
+func transformInsert(ins, del *api_pb.Operation) *api_pb.Operation {
+	switch {
+	case ins.Index <= del.Index:
+		return ins
+	case ins.Index >= del.Index+del.Len:
+		return moved(ins, ins.Index-del.Len)
+	default:
+		return moved(ins, del.Index)
+	}
+}
+
+// transformDelete moves deletion del past the insert ins. An insert inside the
+// deleted range splits the deletion so the inserted text survives.
+func transformDelete(del, ins *api_pb.Operation) []*api_pb.Operation {
+	switch {
+	case ins.Index <= del.Index:
+		return ops(moved(del, del.Index+ins.Len))
+	case ins.Index >= del.Index+del.Len:
+		return ops(del)
+	default:
+		before := ins.Index - del.Index
+		return ops(
+			resized(del, del.Index, before),
+			resized(del, del.Index+ins.Len, del.Len-before),
+		)
+	}
+}
+
+// deleteAfterDelete returns what is left of a once b has been deleted.
+func deleteAfterDelete(a, b *api_pb.Operation) []*api_pb.Operation {
+	aEnd, bEnd := a.Index+a.Len, b.Index+b.Len
+	switch {
+	case aEnd <= b.Index:
+		return ops(a)
+	case bEnd <= a.Index:
+		return ops(moved(a, a.Index-b.Len))
+	}
+
+	overlap := min(aEnd, bEnd) - max(a.Index, b.Index)
+	if a.Len == overlap {
+		return nil
+	}
+	return ops(resized(a, min(a.Index, b.Index), a.Len-overlap))
+}
+
+func ops(ops ...*api_pb.Operation) []*api_pb.Operation {
+	return ops
+}
+
+func moved(op *api_pb.Operation, index int32) *api_pb.Operation {
+	return resized(op, index, op.Len)
+}
+
+func resized(op *api_pb.Operation, index, length int32) *api_pb.Operation {
+	res := &api_pb.Operation{
+		UserID:       op.UserID,
+		Type:         op.Type,
+		Index:        index,
+		Len:          length,
+		Version:      op.Version,
+		Id:           op.Id,
+		ConnectionId: op.ConnectionId,
+	}
+	if op.Type == api_pb.OpType_INSERT {
+		res.Text = op.Text
+	}
+	return res
+}
+
+func min(a, b int32) int32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int32) int32 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,119 @@
+package ot
+
+import (
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"math/rand"
+	"testing"
+)
+
+func ins(index int32, text string) *api_pb.Operation {
+	return &api_pb.Operation{
+		Type:  api_pb.OpType_INSERT,
+		Index: index,
+		Len:   Length(text, api_pb.PositionUnit_CODE_POINTS),
+		Text:  text,
+	}
+}
+
+func del(index, n int32) *api_pb.Operation {
+	return &api_pb.Operation{Type: api_pb.OpType_DELETE, Index: index, Len: n}
+}
+
+// converge applies a then b' and b then a' to text and checks both give want.
+func converge(t *testing.T, text string, a, b []*api_pb.Operation, want string) {
+	t.Helper()
+	ap, bp := Transform(a, b)
+	ab := apply(t, apply(t, text, a), bp)
+	ba := apply(t, apply(t, text, b), ap)
+	if ab != ba {
+		t.Fatalf("diverged: a then b' gives %q, b then a' gives %q", ab, ba)
+	}
+	if want != "" && ab != want {
+		t.Fatalf("got %q, want %q", ab, want)
+	}
+}
+
+func apply(t *testing.T, text string, ops []*api_pb.Operation) string {
+	t.Helper()
+	res, _, err := Apply(text, ops, api_pb.PositionUnit_CODE_POINTS)
+	if err != nil {
+		t.Fatalf("applying %v to %q: %v", ops, text, err)
+	}
+	return res
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		a, b []*api_pb.Operation
+		want string
+	}{
+		{"inserts at the same position", "abc", ops(ins(1, "X")), ops(ins(1, "Y")), "aYXbc"},
+		{"inserts apart", "abc", ops(ins(0, "X")), ops(ins(3, "Y")), "XabcY"},
+		{"insert before a delete", "abcdef", ops(ins(1, "X")), ops(del(2, 2)), "aXbef"},
+		{"insert at the start of a delete", "abcdef", ops(ins(2, "X")), ops(del(2, 2)), "abXef"},
+		{"insert inside a delete", "abcdef", ops(ins(3, "X")), ops(del(1, 4)), "aXf"},
+		{"insert at the end of a delete", "abcdef", ops(ins(4, "X")), ops(del(1, 3)), "aXef"},
+		{"insert after a delete", "abcdef", ops(ins(5, "X")), ops(del(1, 2)), "adeXf"},
+		{"same delete", "abcdef", ops(del(1, 3)), ops(del(1, 3)), "aef"},
+		{"overlapping deletes", "abcdef", ops(del(1, 3)), ops(del(2, 3)), "af"},
+		{"delete inside a delete", "abcdef", ops(del(2, 1)), ops(del(1, 4)), "af"},
+		{"adjacent deletes", "abcdef", ops(del(1, 2)), ops(del(3, 2)), "af"},
+		{"several ops on both sides", "abcdef", ops(ins(0, "X"), del(2, 2)), ops(del(0, 1), ins(4, "Y")), "XdeYf"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converge(t, tt.text, tt.a, tt.b, tt.want)
+		})
+	}
+}
+
+// randomOps returns up to n operations made one after another on text.
+func randomOps(r *rand.Rand, text string, n int) []*api_pb.Operation {
+	var res []*api_pb.Operation
+	length := Length(text, api_pb.PositionUnit_CODE_POINTS)
+	for i := r.Intn(n + 1); i > 0; i-- {
+		if length > 0 && r.Intn(2) == 0 {
+			index := r.Int31n(length)
+			n := 1 + r.Int31n(length-index)
+			res = append(res, del(index, n))
+			length -= n
+			continue
+		}
+		text := []string{"x", "yz", "é", "😀"}[r.Intn(4)]
+		res = append(res, ins(r.Int31n(length+1), text))
+		length += Length(text, api_pb.PositionUnit_CODE_POINTS)
+	}
+	return res
+}
+
+func TestTransformConverges(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		text := string([]rune("abcdefghij😀é")[:r.Intn(13)])
+		converge(t, text, randomOps(r, text, 4), randomOps(r, text, 4), "")
+	}
+}
+
+func TestComposeDiffInvert(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 5000; i++ {
+		text := string([]rune("abcdefghij😀é")[:r.Intn(13)])
+		ops := randomOps(r, text, 6)
+		want, change, err := Apply(text, ops, api_pb.PositionUnit_CODE_POINTS)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := apply(t, text, Compose(ops, api_pb.PositionUnit_CODE_POINTS)); got != want {
+			t.Fatalf("composed %v on %q gives %q, want %q", ops, text, got, want)
+		}
+		if got := apply(t, text, Diff(text, want)); got != want {
+			t.Fatalf("diff of %q and %q gives %q", text, want, got)
+		}
+		if got := apply(t, want, Invert(change.Ops)); got != text {
+			t.Fatalf("inverting %v on %q gives %q, want %q", ops, text, got, text)
+		}
+	}
+}
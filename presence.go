@@ -0,0 +1,119 @@
+package main
+
+import (
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"time"
+)
+
+var participantColors = []string{
+	"#e6194b", "#3cb44b", "#4363d8", "#f58231", "#911eb4",
+	"#46f0f0", "#f032e6", "#bcf60c", "#008080", "#9a6324",
+}
+
+// nextColor returns the first color not used by any of the participants. Once
+// the palette runs out colors start repeating.
+func nextColor(participants []*api_pb.Participant) string {
+	used := make(map[string]int, len(participants))
+	for _, p := range participants {
+		used[p.Color]++
+	}
+
+	for round := 0; ; round++ {
+		for _, color := range participantColors {
+			if used[color] <= round {
+				return color
+			}
+		}
+	}
+}
+
+// participants returns the participants of every client in the session,
+// including the ones connected to other replicas.
+func (s *session) participants() []*api_pb.Participant {
+	res := make([]*api_pb.Participant, 0, len(s.clients))
+	for cl := range s.clients {
+		res = append(res, cl.participant)
+	}
+	for _, r := range s.remote {
+		res = append(res, r.participants...)
+	}
+	return res
+}
+
+// remotePresence holds the participants connected to another replica.
+type remotePresence struct {
+	participants []*api_pb.Participant
+	// cursors are counted in code points, keyed by connection
+	cursors map[string]*api_pb.Cursor
+	seen    time.Time
+}
+
+// publishPresence lets the other replicas know who is connected to this one.
+// hello asks them to answer with their own participants.
+func (s *session) publishPresence(hello bool) {
+	participants := make([]*api_pb.Participant, 0, len(s.clients))
+	for cl := range s.clients {
+		participants = append(participants, cl.participant)
+	}
+	s.publish(&clusterMessage{
+		Kind:         kindPresence,
+		Participants: participants,
+		Hello:        hello,
+	})
+}
+
+// handlePresence updates the participants of another replica and tells the
+// local clients who joined or quit.
+func (s *session) handlePresence(m *clusterMessage) {
+	r, ok := s.remote[m.Instance]
+	if !ok {
+		r = &remotePresence{cursors: make(map[string]*api_pb.Cursor)}
+		s.remote[m.Instance] = r
+	}
+	old := r.participants
+	r.participants = m.Participants
+	r.seen = time.Now()
+
+	present := make(map[string]bool, len(m.Participants))
+	for _, p := range m.Participants {
+		present[p.ConnectionId] = true
+	}
+	for _, p := range old {
+		if !present[p.ConnectionId] {
+			delete(r.cursors, p.ConnectionId)
+			s.notify(api_pb.Event_CLIENT_QUIT, p, nil)
+		}
+		delete(present, p.ConnectionId)
+	}
+	for _, p := range m.Participants {
+		if present[p.ConnectionId] {
+			s.notify(api_pb.Event_CLIENT_JOINED, p, nil)
+		}
+	}
+	if len(m.Participants) == 0 {
+		delete(s.remote, m.Instance)
+	}
+
+	if m.Hello && len(s.clients) > 0 {
+		s.publishPresence(false)
+		for cl := range s.clients {
+			if cl.cursor != nil {
+				s.publish(&clusterMessage{Kind: kindCursor, Cursor: cl.cursor})
+			}
+		}
+	}
+}
+
+// expirePresence drops the participants of replicas that stopped announcing
+// them, most likely because they went down.
+func (s *session) expirePresence() {
+	for instance, r := range s.remote {
+		if time.Since(r.seen) < 3*leaseTTL {
+			continue
+		}
+		delete(s.remote, instance)
+		for _, p := range r.participants {
+			s.notify(api_pb.Event_CLIENT_QUIT, p, nil)
+		}
+	}
+}
@@ -4,8 +4,12 @@
 package api_pb
 
 import (
+	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	io "io"
 	math "math"
 	math_bits "math/bits"
@@ -22,6 +26,72 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Capability is an optional protocol feature.
+type Capability int32
+
+const (
+	Capability_CAPABILITY_UNSPECIFIED Capability = 0
+	// BATCHING is support for OperationBatch. Without it, clients can't send
+	// batches and get the operations of a revision as separate OPERATION
+	// events sharing its version. Clients of the v1 subprotocols don't get it,
+	// but may still send batches.
+	Capability_BATCHING Capability = 1
+	// CURSORS is support for CURSOR events. Without it, clients can't send their
+	// cursor and get no cursors of others.
+	Capability_CURSORS Capability = 2
+	// BINARY is set when events are encoded as protobuf.
+	Capability_BINARY Capability = 3
+)
+
+var Capability_name = map[int32]string{
+	0: "CAPABILITY_UNSPECIFIED",
+	1: "BATCHING",
+	2: "CURSORS",
+	3: "BINARY",
+}
+
+var Capability_value = map[string]int32{
+	"CAPABILITY_UNSPECIFIED": 0,
+	"BATCHING":               1,
+	"CURSORS":                2,
+	"BINARY":                 3,
+}
+
+func (x Capability) String() string {
+	return proto.EnumName(Capability_name, int32(x))
+}
+
+func (Capability) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+// PositionUnit is the unit Operation index and len are counted in. Browser
+// editors usually want UTF16, which matches JavaScript string indices.
+type PositionUnit int32
+
+const (
+	PositionUnit_CODE_POINTS PositionUnit = 0
+	PositionUnit_UTF16       PositionUnit = 1
+)
+
+var PositionUnit_name = map[int32]string{
+	0: "CODE_POINTS",
+	1: "UTF16",
+}
+
+var PositionUnit_value = map[string]int32{
+	"CODE_POINTS": 0,
+	"UTF16":       1,
+}
+
+func (x PositionUnit) String() string {
+	return proto.EnumName(PositionUnit_name, int32(x))
+}
+
+func (PositionUnit) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
+}
+
 type OpType int32
 
 const (
@@ -44,33 +114,147 @@ func (x OpType) String() string {
 }
 
 func (OpType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
+}
+
+type ErrorCode int32
+
+const (
+	ErrorCode_INTERNAL            ErrorCode = 0
+	ErrorCode_MALFORMED_EVENT     ErrorCode = 1
+	ErrorCode_UNSUPPORTED_EVENT   ErrorCode = 2
+	ErrorCode_INVALID_OPERATION   ErrorCode = 3
+	ErrorCode_OUT_OF_RANGE        ErrorCode = 4
+	ErrorCode_UNKNOWN_VERSION     ErrorCode = 5
+	ErrorCode_VERSION_COMPACTED   ErrorCode = 6
+	ErrorCode_INCOMPATIBLE_CLIENT ErrorCode = 7
+	ErrorCode_NOTHING_TO_UNDO     ErrorCode = 8
+	ErrorCode_DOCUMENT_DELETED    ErrorCode = 9
+	ErrorCode_CRDT_SYNC_REQUIRED  ErrorCode = 10
+)
+
+var ErrorCode_name = map[int32]string{
+	0:  "INTERNAL",
+	1:  "MALFORMED_EVENT",
+	2:  "UNSUPPORTED_EVENT",
+	3:  "INVALID_OPERATION",
+	4:  "OUT_OF_RANGE",
+	5:  "UNKNOWN_VERSION",
+	6:  "VERSION_COMPACTED",
+	7:  "INCOMPATIBLE_CLIENT",
+	8:  "NOTHING_TO_UNDO",
+	9:  "DOCUMENT_DELETED",
+	10: "CRDT_SYNC_REQUIRED",
+}
+
+var ErrorCode_value = map[string]int32{
+	"INTERNAL":            0,
+	"MALFORMED_EVENT":     1,
+	"UNSUPPORTED_EVENT":   2,
+	"INVALID_OPERATION":   3,
+	"OUT_OF_RANGE":        4,
+	"UNKNOWN_VERSION":     5,
+	"VERSION_COMPACTED":   6,
+	"INCOMPATIBLE_CLIENT": 7,
+	"NOTHING_TO_UNDO":     8,
+	"DOCUMENT_DELETED":    9,
+	"CRDT_SYNC_REQUIRED":  10,
+}
+
+func (x ErrorCode) String() string {
+	return proto.EnumName(ErrorCode_name, int32(x))
+}
+
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+
+// DocumentMode is how a document is edited. OT documents take Operation
+// events, which the server transforms and orders. CRDT documents take CRDT
+// updates, which every replica merges in any order, so clients can keep
+// editing offline for long and sync later.
+type DocumentMode int32
+
+const (
+	DocumentMode_OT   DocumentMode = 0
+	DocumentMode_CRDT DocumentMode = 1
+)
+
+var DocumentMode_name = map[int32]string{
+	0: "OT",
+	1: "CRDT",
+}
+
+var DocumentMode_value = map[string]int32{
+	"OT":   0,
+	"CRDT": 1,
+}
+
+func (x DocumentMode) String() string {
+	return proto.EnumName(DocumentMode_name, int32(x))
+}
+
+func (DocumentMode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
 }
 
 type Event_EventType int32
 
 const (
-	Event_INIT          Event_EventType = 0
-	Event_CLIENT_JOINED Event_EventType = 1
-	Event_CLIENT_QUIT   Event_EventType = 2
-	Event_OPERATION     Event_EventType = 3
-	Event_OPERATION_ACK Event_EventType = 4
+	Event_INIT            Event_EventType = 0
+	Event_CLIENT_JOINED   Event_EventType = 1
+	Event_CLIENT_QUIT     Event_EventType = 2
+	Event_OPERATION       Event_EventType = 3
+	Event_OPERATION_ACK   Event_EventType = 4
+	Event_CURSOR          Event_EventType = 5
+	Event_RESUME          Event_EventType = 6
+	Event_ERROR           Event_EventType = 7
+	Event_CHECKSUM        Event_EventType = 8
+	Event_RESYNC          Event_EventType = 9
+	Event_OPERATION_BATCH Event_EventType = 10
+	Event_HELLO           Event_EventType = 11
+	Event_CRDT_SYNC       Event_EventType = 12
+	Event_CRDT_UPDATE     Event_EventType = 13
+	Event_UNDO            Event_EventType = 14
+	Event_REDO            Event_EventType = 15
 )
 
 var Event_EventType_name = map[int32]string{
-	0: "INIT",
-	1: "CLIENT_JOINED",
-	2: "CLIENT_QUIT",
-	3: "OPERATION",
-	4: "OPERATION_ACK",
+	0:  "INIT",
+	1:  "CLIENT_JOINED",
+	2:  "CLIENT_QUIT",
+	3:  "OPERATION",
+	4:  "OPERATION_ACK",
+	5:  "CURSOR",
+	6:  "RESUME",
+	7:  "ERROR",
+	8:  "CHECKSUM",
+	9:  "RESYNC",
+	10: "OPERATION_BATCH",
+	11: "HELLO",
+	12: "CRDT_SYNC",
+	13: "CRDT_UPDATE",
+	14: "UNDO",
+	15: "REDO",
 }
 
 var Event_EventType_value = map[string]int32{
-	"INIT":          0,
-	"CLIENT_JOINED": 1,
-	"CLIENT_QUIT":   2,
-	"OPERATION":     3,
-	"OPERATION_ACK": 4,
+	"INIT":            0,
+	"CLIENT_JOINED":   1,
+	"CLIENT_QUIT":     2,
+	"OPERATION":       3,
+	"OPERATION_ACK":   4,
+	"CURSOR":          5,
+	"RESUME":          6,
+	"ERROR":           7,
+	"CHECKSUM":        8,
+	"RESYNC":          9,
+	"OPERATION_BATCH": 10,
+	"HELLO":           11,
+	"CRDT_SYNC":       12,
+	"CRDT_UPDATE":     13,
+	"UNDO":            14,
+	"REDO":            15,
 }
 
 func (x Event_EventType) String() string {
@@ -81,6 +265,9 @@ func (Event_EventType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{0, 0}
 }
 
+// Event is a message on the document websocket with the cloudocs.json.v1 and
+// cloudocs.proto.v1 subprotocols. event holds the payload of the given type,
+// encoded the same way as the event itself: as JSON or as protobuf.
 type Event struct {
 	Type                 Event_EventType `protobuf:"varint,1,opt,name=type,proto3,enum=api_pb.Event_EventType" json:"type,omitempty"`
 	Event                []byte          `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
@@ -136,27 +323,47 @@ func (m *Event) GetEvent() []byte {
 	return nil
 }
 
-type Init struct {
-	DocumentName         string   `protobuf:"bytes,1,opt,name=document_name,json=documentName,proto3" json:"document_name,omitempty"`
-	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
-	LastVersion          int32    `protobuf:"varint,3,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+// Envelope is a message on the document websocket with the cloudocs.proto.v2
+// and cloudocs.json.v2 subprotocols, replacing Event. request_id is chosen by
+// the client for what it sends and echoed on the OperationAck or Error that
+// answers it.
+type Envelope struct {
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Types that are valid to be assigned to Event:
+	//	*Envelope_Init
+	//	*Envelope_ClientJoined
+	//	*Envelope_ClientQuit
+	//	*Envelope_Operation
+	//	*Envelope_OperationAck
+	//	*Envelope_Cursor
+	//	*Envelope_Resume
+	//	*Envelope_Error
+	//	*Envelope_Checksum
+	//	*Envelope_Resync
+	//	*Envelope_OperationBatch
+	//	*Envelope_Hello
+	//	*Envelope_CrdtSync
+	//	*Envelope_CrdtUpdate
+	//	*Envelope_Undo
+	//	*Envelope_Redo
+	Event                isEnvelope_Event `protobuf_oneof:"event"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
-func (m *Init) Reset()         { *m = Init{} }
-func (m *Init) String() string { return proto.CompactTextString(m) }
-func (*Init) ProtoMessage()    {}
-func (*Init) Descriptor() ([]byte, []int) {
+func (m *Envelope) Reset()         { *m = Envelope{} }
+func (m *Envelope) String() string { return proto.CompactTextString(m) }
+func (*Envelope) ProtoMessage()    {}
+func (*Envelope) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
 }
-func (m *Init) XXX_Unmarshal(b []byte) error {
+func (m *Envelope) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Init) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Envelope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Init.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Envelope.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -166,63 +373,265 @@ func (m *Init) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Init) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Init.Merge(m, src)
+func (m *Envelope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Envelope.Merge(m, src)
 }
-func (m *Init) XXX_Size() int {
+func (m *Envelope) XXX_Size() int {
 	return m.Size()
 }
-func (m *Init) XXX_DiscardUnknown() {
-	xxx_messageInfo_Init.DiscardUnknown(m)
+func (m *Envelope) XXX_DiscardUnknown() {
+	xxx_messageInfo_Envelope.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Init proto.InternalMessageInfo
+var xxx_messageInfo_Envelope proto.InternalMessageInfo
 
-func (m *Init) GetDocumentName() string {
+type isEnvelope_Event interface {
+	isEnvelope_Event()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type Envelope_Init struct {
+	Init *Init `protobuf:"bytes,2,opt,name=init,proto3,oneof" json:"init,omitempty"`
+}
+type Envelope_ClientJoined struct {
+	ClientJoined *Participant `protobuf:"bytes,3,opt,name=client_joined,json=clientJoined,proto3,oneof" json:"client_joined,omitempty"`
+}
+type Envelope_ClientQuit struct {
+	ClientQuit *Participant `protobuf:"bytes,4,opt,name=client_quit,json=clientQuit,proto3,oneof" json:"client_quit,omitempty"`
+}
+type Envelope_Operation struct {
+	Operation *Operation `protobuf:"bytes,5,opt,name=operation,proto3,oneof" json:"operation,omitempty"`
+}
+type Envelope_OperationAck struct {
+	OperationAck *OperationAck `protobuf:"bytes,6,opt,name=operation_ack,json=operationAck,proto3,oneof" json:"operation_ack,omitempty"`
+}
+type Envelope_Cursor struct {
+	Cursor *Cursor `protobuf:"bytes,7,opt,name=cursor,proto3,oneof" json:"cursor,omitempty"`
+}
+type Envelope_Resume struct {
+	Resume *Resume `protobuf:"bytes,8,opt,name=resume,proto3,oneof" json:"resume,omitempty"`
+}
+type Envelope_Error struct {
+	Error *Error `protobuf:"bytes,9,opt,name=error,proto3,oneof" json:"error,omitempty"`
+}
+type Envelope_Checksum struct {
+	Checksum *Checksum `protobuf:"bytes,10,opt,name=checksum,proto3,oneof" json:"checksum,omitempty"`
+}
+type Envelope_Resync struct {
+	Resync *Resync `protobuf:"bytes,11,opt,name=resync,proto3,oneof" json:"resync,omitempty"`
+}
+type Envelope_OperationBatch struct {
+	OperationBatch *OperationBatch `protobuf:"bytes,12,opt,name=operation_batch,json=operationBatch,proto3,oneof" json:"operation_batch,omitempty"`
+}
+type Envelope_Hello struct {
+	Hello *Hello `protobuf:"bytes,13,opt,name=hello,proto3,oneof" json:"hello,omitempty"`
+}
+type Envelope_CrdtSync struct {
+	CrdtSync *CrdtSync `protobuf:"bytes,14,opt,name=crdt_sync,json=crdtSync,proto3,oneof" json:"crdt_sync,omitempty"`
+}
+type Envelope_CrdtUpdate struct {
+	CrdtUpdate *CrdtUpdate `protobuf:"bytes,15,opt,name=crdt_update,json=crdtUpdate,proto3,oneof" json:"crdt_update,omitempty"`
+}
+type Envelope_Undo struct {
+	Undo *Undo `protobuf:"bytes,16,opt,name=undo,proto3,oneof" json:"undo,omitempty"`
+}
+type Envelope_Redo struct {
+	Redo *Redo `protobuf:"bytes,17,opt,name=redo,proto3,oneof" json:"redo,omitempty"`
+}
+
+func (*Envelope_Init) isEnvelope_Event()           {}
+func (*Envelope_ClientJoined) isEnvelope_Event()   {}
+func (*Envelope_ClientQuit) isEnvelope_Event()     {}
+func (*Envelope_Operation) isEnvelope_Event()      {}
+func (*Envelope_OperationAck) isEnvelope_Event()   {}
+func (*Envelope_Cursor) isEnvelope_Event()         {}
+func (*Envelope_Resume) isEnvelope_Event()         {}
+func (*Envelope_Error) isEnvelope_Event()          {}
+func (*Envelope_Checksum) isEnvelope_Event()       {}
+func (*Envelope_Resync) isEnvelope_Event()         {}
+func (*Envelope_OperationBatch) isEnvelope_Event() {}
+func (*Envelope_Hello) isEnvelope_Event()          {}
+func (*Envelope_CrdtSync) isEnvelope_Event()       {}
+func (*Envelope_CrdtUpdate) isEnvelope_Event()     {}
+func (*Envelope_Undo) isEnvelope_Event()           {}
+func (*Envelope_Redo) isEnvelope_Event()           {}
+
+func (m *Envelope) GetEvent() isEnvelope_Event {
 	if m != nil {
-		return m.DocumentName
+		return m.Event
 	}
-	return ""
+	return nil
 }
 
-func (m *Init) GetText() string {
+func (m *Envelope) GetRequestId() string {
 	if m != nil {
-		return m.Text
+		return m.RequestId
 	}
 	return ""
 }
 
-func (m *Init) GetLastVersion() int32 {
-	if m != nil {
-		return m.LastVersion
+func (m *Envelope) GetInit() *Init {
+	if x, ok := m.GetEvent().(*Envelope_Init); ok {
+		return x.Init
 	}
-	return 0
+	return nil
 }
 
-type Operation struct {
-	UserID               string   `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
-	Type                 OpType   `protobuf:"varint,2,opt,name=type,proto3,enum=api_pb.OpType" json:"type,omitempty"`
-	Index                int32    `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
-	Len                  int32    `protobuf:"varint,4,opt,name=len,proto3" json:"len,omitempty"`
-	Text                 string   `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
-	Version              int32    `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Envelope) GetClientJoined() *Participant {
+	if x, ok := m.GetEvent().(*Envelope_ClientJoined); ok {
+		return x.ClientJoined
+	}
+	return nil
 }
 
-func (m *Operation) Reset()         { *m = Operation{} }
-func (m *Operation) String() string { return proto.CompactTextString(m) }
-func (*Operation) ProtoMessage()    {}
-func (*Operation) Descriptor() ([]byte, []int) {
+func (m *Envelope) GetClientQuit() *Participant {
+	if x, ok := m.GetEvent().(*Envelope_ClientQuit); ok {
+		return x.ClientQuit
+	}
+	return nil
+}
+
+func (m *Envelope) GetOperation() *Operation {
+	if x, ok := m.GetEvent().(*Envelope_Operation); ok {
+		return x.Operation
+	}
+	return nil
+}
+
+func (m *Envelope) GetOperationAck() *OperationAck {
+	if x, ok := m.GetEvent().(*Envelope_OperationAck); ok {
+		return x.OperationAck
+	}
+	return nil
+}
+
+func (m *Envelope) GetCursor() *Cursor {
+	if x, ok := m.GetEvent().(*Envelope_Cursor); ok {
+		return x.Cursor
+	}
+	return nil
+}
+
+func (m *Envelope) GetResume() *Resume {
+	if x, ok := m.GetEvent().(*Envelope_Resume); ok {
+		return x.Resume
+	}
+	return nil
+}
+
+func (m *Envelope) GetError() *Error {
+	if x, ok := m.GetEvent().(*Envelope_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+func (m *Envelope) GetChecksum() *Checksum {
+	if x, ok := m.GetEvent().(*Envelope_Checksum); ok {
+		return x.Checksum
+	}
+	return nil
+}
+
+func (m *Envelope) GetResync() *Resync {
+	if x, ok := m.GetEvent().(*Envelope_Resync); ok {
+		return x.Resync
+	}
+	return nil
+}
+
+func (m *Envelope) GetOperationBatch() *OperationBatch {
+	if x, ok := m.GetEvent().(*Envelope_OperationBatch); ok {
+		return x.OperationBatch
+	}
+	return nil
+}
+
+func (m *Envelope) GetHello() *Hello {
+	if x, ok := m.GetEvent().(*Envelope_Hello); ok {
+		return x.Hello
+	}
+	return nil
+}
+
+func (m *Envelope) GetCrdtSync() *CrdtSync {
+	if x, ok := m.GetEvent().(*Envelope_CrdtSync); ok {
+		return x.CrdtSync
+	}
+	return nil
+}
+
+func (m *Envelope) GetCrdtUpdate() *CrdtUpdate {
+	if x, ok := m.GetEvent().(*Envelope_CrdtUpdate); ok {
+		return x.CrdtUpdate
+	}
+	return nil
+}
+
+func (m *Envelope) GetUndo() *Undo {
+	if x, ok := m.GetEvent().(*Envelope_Undo); ok {
+		return x.Undo
+	}
+	return nil
+}
+
+func (m *Envelope) GetRedo() *Redo {
+	if x, ok := m.GetEvent().(*Envelope_Redo); ok {
+		return x.Redo
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Envelope) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Envelope_Init)(nil),
+		(*Envelope_ClientJoined)(nil),
+		(*Envelope_ClientQuit)(nil),
+		(*Envelope_Operation)(nil),
+		(*Envelope_OperationAck)(nil),
+		(*Envelope_Cursor)(nil),
+		(*Envelope_Resume)(nil),
+		(*Envelope_Error)(nil),
+		(*Envelope_Checksum)(nil),
+		(*Envelope_Resync)(nil),
+		(*Envelope_OperationBatch)(nil),
+		(*Envelope_Hello)(nil),
+		(*Envelope_CrdtSync)(nil),
+		(*Envelope_CrdtUpdate)(nil),
+		(*Envelope_Undo)(nil),
+		(*Envelope_Redo)(nil),
+	}
+}
+
+// Hello is the first message of a client with the v2 subprotocols, which the
+// server answers with Init or Resume, carrying the same request_id. The server
+// rejects clients older than the protocol versions it supports with an
+// INCOMPATIBLE_CLIENT error and closes the connection. position_unit replaces
+// the unit query parameter. capabilities lists the features the client
+// supports.
+type Hello struct {
+	ProtocolVersion      int32        `protobuf:"varint,1,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	PositionUnit         PositionUnit `protobuf:"varint,2,opt,name=position_unit,json=positionUnit,proto3,enum=api_pb.PositionUnit" json:"position_unit,omitempty"`
+	Capabilities         []Capability `protobuf:"varint,3,rep,packed,name=capabilities,proto3,enum=api_pb.Capability" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Hello) Reset()         { *m = Hello{} }
+func (m *Hello) String() string { return proto.CompactTextString(m) }
+func (*Hello) ProtoMessage()    {}
+func (*Hello) Descriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
 }
-func (m *Operation) XXX_Unmarshal(b []byte) error {
+func (m *Hello) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *Operation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Hello) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_Operation.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Hello.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -232,79 +641,141 @@ func (m *Operation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *Operation) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Operation.Merge(m, src)
+func (m *Hello) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Hello.Merge(m, src)
 }
-func (m *Operation) XXX_Size() int {
+func (m *Hello) XXX_Size() int {
 	return m.Size()
 }
-func (m *Operation) XXX_DiscardUnknown() {
-	xxx_messageInfo_Operation.DiscardUnknown(m)
+func (m *Hello) XXX_DiscardUnknown() {
+	xxx_messageInfo_Hello.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Operation proto.InternalMessageInfo
+var xxx_messageInfo_Hello proto.InternalMessageInfo
 
-func (m *Operation) GetUserID() string {
+func (m *Hello) GetProtocolVersion() int32 {
 	if m != nil {
-		return m.UserID
+		return m.ProtocolVersion
 	}
-	return ""
+	return 0
 }
 
-func (m *Operation) GetType() OpType {
+func (m *Hello) GetPositionUnit() PositionUnit {
 	if m != nil {
-		return m.Type
+		return m.PositionUnit
 	}
-	return OpType_INSERT
+	return PositionUnit_CODE_POINTS
 }
 
-func (m *Operation) GetIndex() int32 {
+func (m *Hello) GetCapabilities() []Capability {
 	if m != nil {
-		return m.Index
+		return m.Capabilities
 	}
-	return 0
+	return nil
 }
 
-func (m *Operation) GetLen() int32 {
+// Participant is a connection to a document. A user connected from several
+// tabs or devices is a participant once per connection, told apart by the
+// connection_id the server assigns. It is the payload of CLIENT_JOINED and
+// CLIENT_QUIT events.
+type Participant struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color                string   `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	ConnectionId         string   `protobuf:"bytes,4,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Participant) Reset()         { *m = Participant{} }
+func (m *Participant) String() string { return proto.CompactTextString(m) }
+func (*Participant) ProtoMessage()    {}
+func (*Participant) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+func (m *Participant) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Participant) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Participant.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Participant) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Participant.Merge(m, src)
+}
+func (m *Participant) XXX_Size() int {
+	return m.Size()
+}
+func (m *Participant) XXX_DiscardUnknown() {
+	xxx_messageInfo_Participant.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Participant proto.InternalMessageInfo
+
+func (m *Participant) GetUserId() string {
 	if m != nil {
-		return m.Len
+		return m.UserId
 	}
-	return 0
+	return ""
 }
 
-func (m *Operation) GetText() string {
+func (m *Participant) GetName() string {
 	if m != nil {
-		return m.Text
+		return m.Name
 	}
 	return ""
 }
 
-func (m *Operation) GetVersion() int32 {
+func (m *Participant) GetColor() string {
 	if m != nil {
-		return m.Version
+		return m.Color
 	}
-	return 0
+	return ""
 }
 
-type OperationAck struct {
-	LastVersion          int32    `protobuf:"varint,1,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
+func (m *Participant) GetConnectionId() string {
+	if m != nil {
+		return m.ConnectionId
+	}
+	return ""
+}
+
+// Cursor is the caret and selection of a connection. anchor and head are
+// equal when nothing is selected. When sent by a client, version is the
+// version the positions refer to; the server sends cursors at the latest
+// version, with user_id and connection_id set.
+type Cursor struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Anchor               int32    `protobuf:"varint,2,opt,name=anchor,proto3" json:"anchor,omitempty"`
+	Head                 int32    `protobuf:"varint,3,opt,name=head,proto3" json:"head,omitempty"`
+	Version              int32    `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	ConnectionId         string   `protobuf:"bytes,5,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *OperationAck) Reset()         { *m = OperationAck{} }
-func (m *OperationAck) String() string { return proto.CompactTextString(m) }
-func (*OperationAck) ProtoMessage()    {}
-func (*OperationAck) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+func (m *Cursor) Reset()         { *m = Cursor{} }
+func (m *Cursor) String() string { return proto.CompactTextString(m) }
+func (*Cursor) ProtoMessage()    {}
+func (*Cursor) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
 }
-func (m *OperationAck) XXX_Unmarshal(b []byte) error {
+func (m *Cursor) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *OperationAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *Cursor) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_OperationAck.Marshal(b, m, deterministic)
+		return xxx_messageInfo_Cursor.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -314,351 +785,9161 @@ func (m *OperationAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error)
 		return b[:n], nil
 	}
 }
-func (m *OperationAck) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_OperationAck.Merge(m, src)
+func (m *Cursor) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Cursor.Merge(m, src)
 }
-func (m *OperationAck) XXX_Size() int {
+func (m *Cursor) XXX_Size() int {
 	return m.Size()
 }
-func (m *OperationAck) XXX_DiscardUnknown() {
-	xxx_messageInfo_OperationAck.DiscardUnknown(m)
+func (m *Cursor) XXX_DiscardUnknown() {
+	xxx_messageInfo_Cursor.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_OperationAck proto.InternalMessageInfo
+var xxx_messageInfo_Cursor proto.InternalMessageInfo
 
-func (m *OperationAck) GetLastVersion() int32 {
+func (m *Cursor) GetUserId() string {
 	if m != nil {
-		return m.LastVersion
+		return m.UserId
 	}
-	return 0
+	return ""
 }
 
-func init() {
-	proto.RegisterEnum("api_pb.OpType", OpType_name, OpType_value)
-	proto.RegisterEnum("api_pb.Event_EventType", Event_EventType_name, Event_EventType_value)
-	proto.RegisterType((*Event)(nil), "api_pb.Event")
-	proto.RegisterType((*Init)(nil), "api_pb.Init")
-	proto.RegisterType((*Operation)(nil), "api_pb.Operation")
-	proto.RegisterType((*OperationAck)(nil), "api_pb.OperationAck")
+func (m *Cursor) GetAnchor() int32 {
+	if m != nil {
+		return m.Anchor
+	}
+	return 0
 }
 
-func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+func (m *Cursor) GetHead() int32 {
+	if m != nil {
+		return m.Head
+	}
+	return 0
+}
 
-var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 415 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x41, 0x8e, 0xd3, 0x30,
-	0x18, 0x85, 0xc7, 0x6d, 0x1a, 0xc8, 0x3f, 0xed, 0x10, 0x2c, 0x04, 0x59, 0x55, 0x25, 0x6c, 0x2a,
-	0xd0, 0xb4, 0x02, 0x76, 0xec, 0xca, 0xd4, 0x0b, 0xc3, 0x28, 0x81, 0x10, 0x58, 0xc0, 0x22, 0x72,
-	0x53, 0x03, 0x16, 0xad, 0x6d, 0x25, 0xce, 0x68, 0xe6, 0x26, 0x6c, 0x58, 0x73, 0x15, 0x96, 0x1c,
-	0x01, 0x95, 0x8b, 0xa0, 0xc4, 0x49, 0xc4, 0x68, 0x36, 0x91, 0xdf, 0xcb, 0x8b, 0xfe, 0xef, 0x7f,
-	0x31, 0x78, 0x4c, 0x8b, 0x85, 0x2e, 0x94, 0x51, 0xd8, 0x65, 0x5a, 0x64, 0x7a, 0x13, 0xfe, 0x44,
-	0x30, 0x22, 0x17, 0x5c, 0x1a, 0xfc, 0x04, 0x1c, 0x73, 0xa5, 0x79, 0x80, 0x66, 0x68, 0x7e, 0xf2,
-	0xec, 0xc1, 0xc2, 0x06, 0x16, 0xcd, 0x4b, 0xfb, 0x4c, 0xaf, 0x34, 0x4f, 0x9a, 0x10, 0xbe, 0x07,
-	0x23, 0x5e, 0x5b, 0xc1, 0x60, 0x86, 0xe6, 0xe3, 0xc4, 0x8a, 0xf0, 0x13, 0x78, 0x7d, 0x10, 0xdf,
-	0x06, 0x87, 0x46, 0x34, 0xf5, 0x8f, 0xf0, 0x5d, 0x98, 0x9c, 0x9d, 0x53, 0x12, 0xa5, 0xd9, 0xab,
-	0x98, 0x46, 0x64, 0xed, 0x23, 0x7c, 0x07, 0x8e, 0x5b, 0xeb, 0xed, 0x7b, 0x9a, 0xfa, 0x03, 0x3c,
-	0x01, 0x2f, 0x7e, 0x43, 0x92, 0x55, 0x4a, 0xe3, 0xc8, 0x1f, 0xd6, 0x9f, 0xf4, 0x32, 0x5b, 0x9d,
-	0xbd, 0xf6, 0x9d, 0x70, 0x03, 0x0e, 0x95, 0xc2, 0xe0, 0x47, 0x30, 0xd9, 0xaa, 0xbc, 0xda, 0x73,
-	0x69, 0x32, 0xc9, 0xf6, 0x16, 0xd8, 0x4b, 0xc6, 0x9d, 0x19, 0xb1, 0x3d, 0xc7, 0x18, 0x1c, 0xc3,
-	0x2f, 0x2d, 0x9e, 0x97, 0x34, 0x67, 0xfc, 0x10, 0xc6, 0x3b, 0x56, 0x9a, 0xec, 0x82, 0x17, 0xa5,
-	0x50, 0x32, 0x18, 0xce, 0xd0, 0x7c, 0x94, 0x1c, 0xd7, 0xde, 0x07, 0x6b, 0x85, 0x3f, 0x10, 0x78,
-	0xb1, 0xe6, 0x05, 0x33, 0x42, 0x49, 0x7c, 0x1f, 0xdc, 0xaa, 0xe4, 0x05, 0x5d, 0xb7, 0x23, 0x5a,
-	0x85, 0xc3, 0xb6, 0xa9, 0x41, 0xd3, 0xd4, 0x49, 0xd7, 0x54, 0xac, 0xaf, 0x17, 0x24, 0xe4, 0x96,
-	0x5f, 0xb6, 0x53, 0xac, 0xc0, 0x3e, 0x0c, 0x77, 0x5c, 0x06, 0x4e, 0xe3, 0xd5, 0xc7, 0x1e, 0x74,
-	0xf4, 0x1f, 0x68, 0x00, 0xb7, 0x3a, 0x46, 0xb7, 0x49, 0x76, 0x32, 0x7c, 0x0a, 0xe3, 0x1e, 0x6f,
-	0x95, 0x7f, 0xbb, 0xb1, 0x12, 0xba, 0xb1, 0xd2, 0xe3, 0x19, 0xb8, 0x16, 0x0c, 0x03, 0xb8, 0x34,
-	0x7a, 0x47, 0x92, 0xfa, 0x97, 0x00, 0xb8, 0x6b, 0x72, 0x4e, 0x52, 0xe2, 0xa3, 0x97, 0x2f, 0x7e,
-	0x1d, 0xa6, 0xe8, 0xf7, 0x61, 0x8a, 0xfe, 0x1c, 0xa6, 0xe8, 0xfb, 0xdf, 0xe9, 0xd1, 0xc7, 0xf9,
-	0x17, 0x61, 0xbe, 0x56, 0x9b, 0x45, 0xae, 0xf6, 0xcb, 0xb2, 0x64, 0xd5, 0xe9, 0x67, 0x21, 0xcc,
-	0x32, 0xdf, 0xa9, 0x6a, 0xab, 0xf2, 0xf2, 0x94, 0x69, 0xb1, 0xb4, 0x3b, 0x6f, 0xdc, 0xe6, 0x36,
-	0x3d, 0xff, 0x17, 0x00, 0x00, 0xff, 0xff, 0x9f, 0xa9, 0x1f, 0xd3, 0x5a, 0x02, 0x00, 0x00,
+func (m *Cursor) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
 }
 
-func (m *Event) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *Cursor) GetConnectionId() string {
+	if m != nil {
+		return m.ConnectionId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Event) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// Init is sent when a client connects. position_unit is the unit the client
+// asked for with the unit query parameter and the server will use for every
+// operation on this connection. participants lists everyone connected to the
+// document, including the client itself, and cursors the last known cursor
+// of every participant that has sent one. connection_id identifies this
+// connection among the participants. protocol_version and capabilities are
+// the ones negotiated: the lower of the client and server protocol versions
+// and the capabilities both support. Clients that don't send Hello speak
+// version 1 and get CURSORS, plus BINARY on the binary subprotocol. mode
+// tells how the document is edited; text is only a plain view of CRDT
+// documents, whose clients continue with CRDT_SYNC.
+type Init struct {
+	DocumentName         string         `protobuf:"bytes,1,opt,name=document_name,json=documentName,proto3" json:"document_name,omitempty"`
+	Text                 string         `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	LastVersion          int32          `protobuf:"varint,3,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
+	PositionUnit         PositionUnit   `protobuf:"varint,4,opt,name=position_unit,json=positionUnit,proto3,enum=api_pb.PositionUnit" json:"position_unit,omitempty"`
+	Participants         []*Participant `protobuf:"bytes,5,rep,name=participants,proto3" json:"participants,omitempty"`
+	Cursors              []*Cursor      `protobuf:"bytes,6,rep,name=cursors,proto3" json:"cursors,omitempty"`
+	ConnectionId         string         `protobuf:"bytes,7,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	ProtocolVersion      int32          `protobuf:"varint,8,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Capabilities         []Capability   `protobuf:"varint,9,rep,packed,name=capabilities,proto3,enum=api_pb.Capability" json:"capabilities,omitempty"`
+	Mode                 DocumentMode   `protobuf:"varint,10,opt,name=mode,proto3,enum=api_pb.DocumentMode" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
-func (m *Event) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
-	}
-	if len(m.Event) > 0 {
-		i -= len(m.Event)
-		copy(dAtA[i:], m.Event)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.Event)))
-		i--
-		dAtA[i] = 0x12
-	}
-	if m.Type != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.Type))
-		i--
-		dAtA[i] = 0x8
+func (m *Init) Reset()         { *m = Init{} }
+func (m *Init) String() string { return proto.CompactTextString(m) }
+func (*Init) ProtoMessage()    {}
+func (*Init) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+}
+func (m *Init) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Init) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Init.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	return len(dAtA) - i, nil
+}
+func (m *Init) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Init.Merge(m, src)
+}
+func (m *Init) XXX_Size() int {
+	return m.Size()
+}
+func (m *Init) XXX_DiscardUnknown() {
+	xxx_messageInfo_Init.DiscardUnknown(m)
 }
 
-func (m *Init) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_Init proto.InternalMessageInfo
+
+func (m *Init) GetDocumentName() string {
+	if m != nil {
+		return m.DocumentName
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Init) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *Init) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
 }
 
-func (m *Init) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
+func (m *Init) GetLastVersion() int32 {
+	if m != nil {
+		return m.LastVersion
 	}
-	if m.LastVersion != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.LastVersion))
-		i--
-		dAtA[i] = 0x18
+	return 0
+}
+
+func (m *Init) GetPositionUnit() PositionUnit {
+	if m != nil {
+		return m.PositionUnit
 	}
-	if len(m.Text) > 0 {
-		i -= len(m.Text)
-		copy(dAtA[i:], m.Text)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
-		i--
-		dAtA[i] = 0x12
+	return PositionUnit_CODE_POINTS
+}
+
+func (m *Init) GetParticipants() []*Participant {
+	if m != nil {
+		return m.Participants
 	}
-	if len(m.DocumentName) > 0 {
-		i -= len(m.DocumentName)
-		copy(dAtA[i:], m.DocumentName)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.DocumentName)))
-		i--
-		dAtA[i] = 0xa
+	return nil
+}
+
+func (m *Init) GetCursors() []*Cursor {
+	if m != nil {
+		return m.Cursors
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *Operation) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *Init) GetConnectionId() string {
+	if m != nil {
+		return m.ConnectionId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Operation) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *Init) GetProtocolVersion() int32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
 }
 
-func (m *Operation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
+func (m *Init) GetCapabilities() []Capability {
+	if m != nil {
+		return m.Capabilities
 	}
-	if m.Version != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.Version))
-		i--
-		dAtA[i] = 0x30
+	return nil
+}
+
+func (m *Init) GetMode() DocumentMode {
+	if m != nil {
+		return m.Mode
 	}
-	if len(m.Text) > 0 {
-		i -= len(m.Text)
-		copy(dAtA[i:], m.Text)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
-		i--
-		dAtA[i] = 0x2a
+	return DocumentMode_OT
+}
+
+// Resume is sent instead of Init to a client that reconnects with the version
+// query parameter, as long as the server still has every operation applied
+// since that version. operations are the ones the client missed, in order.
+// The client should recognise its own unacknowledged operations among them by
+// id and send the remaining ones again, based on the version it resumed from.
+// Every operation of a batch carries the id of the batch.
+// connection_id, protocol_version and capabilities are set as in Init.
+type Resume struct {
+	LastVersion          int32          `protobuf:"varint,1,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
+	Operations           []*Operation   `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Participants         []*Participant `protobuf:"bytes,3,rep,name=participants,proto3" json:"participants,omitempty"`
+	Cursors              []*Cursor      `protobuf:"bytes,4,rep,name=cursors,proto3" json:"cursors,omitempty"`
+	ConnectionId         string         `protobuf:"bytes,5,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	ProtocolVersion      int32          `protobuf:"varint,6,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	Capabilities         []Capability   `protobuf:"varint,7,rep,packed,name=capabilities,proto3,enum=api_pb.Capability" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Resume) Reset()         { *m = Resume{} }
+func (m *Resume) String() string { return proto.CompactTextString(m) }
+func (*Resume) ProtoMessage()    {}
+func (*Resume) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+func (m *Resume) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Resume) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Resume.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.Len != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.Len))
-		i--
-		dAtA[i] = 0x20
+}
+func (m *Resume) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Resume.Merge(m, src)
+}
+func (m *Resume) XXX_Size() int {
+	return m.Size()
+}
+func (m *Resume) XXX_DiscardUnknown() {
+	xxx_messageInfo_Resume.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Resume proto.InternalMessageInfo
+
+func (m *Resume) GetLastVersion() int32 {
+	if m != nil {
+		return m.LastVersion
 	}
-	if m.Index != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.Index))
-		i--
-		dAtA[i] = 0x18
+	return 0
+}
+
+func (m *Resume) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
 	}
-	if m.Type != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.Type))
-		i--
-		dAtA[i] = 0x10
+	return nil
+}
+
+func (m *Resume) GetParticipants() []*Participant {
+	if m != nil {
+		return m.Participants
 	}
-	if len(m.UserID) > 0 {
-		i -= len(m.UserID)
-		copy(dAtA[i:], m.UserID)
-		i = encodeVarintApi(dAtA, i, uint64(len(m.UserID)))
-		i--
-		dAtA[i] = 0xa
+	return nil
+}
+
+func (m *Resume) GetCursors() []*Cursor {
+	if m != nil {
+		return m.Cursors
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func (m *OperationAck) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+func (m *Resume) GetConnectionId() string {
+	if m != nil {
+		return m.ConnectionId
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *OperationAck) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func (m *Resume) GetProtocolVersion() int32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
 }
 
-func (m *OperationAck) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.XXX_unrecognized != nil {
-		i -= len(m.XXX_unrecognized)
-		copy(dAtA[i:], m.XXX_unrecognized)
-	}
-	if m.LastVersion != 0 {
-		i = encodeVarintApi(dAtA, i, uint64(m.LastVersion))
-		i--
-		dAtA[i] = 0x8
+func (m *Resume) GetCapabilities() []Capability {
+	if m != nil {
+		return m.Capabilities
 	}
-	return len(dAtA) - i, nil
+	return nil
 }
 
-func encodeVarintApi(dAtA []byte, offset int, v uint64) int {
-	offset -= sovApi(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+// Operation is a single insert or delete. When sent by a client, version is the
+// last server version the client had seen when it made the change. When sent by
+// the server, version is the version the operation was applied at. Delete
+// removes len characters starting at index. Empty inserts and deletes are
+// rejected with INVALID_OPERATION. Positions are counted in the
+// PositionUnit negotiated in Init. id is chosen by the client and lets the
+// server recognise an operation sent again after a reconnect. It has to be
+// unique among the operations of the same user in the document; other users
+// may use the same ids. Operations sent by the server carry the checksum of
+// the document text after their version and the connection_id of the
+// connection that made them.
+type Operation struct {
+	UserID               string   `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Type                 OpType   `protobuf:"varint,2,opt,name=type,proto3,enum=api_pb.OpType" json:"type,omitempty"`
+	Index                int32    `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	Len                  int32    `protobuf:"varint,4,opt,name=len,proto3" json:"len,omitempty"`
+	Text                 string   `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Version              int32    `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	Id                   string   `protobuf:"bytes,7,opt,name=id,proto3" json:"id,omitempty"`
+	Checksum             uint32   `protobuf:"varint,8,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	ConnectionId         string   `protobuf:"bytes,9,opt,name=connection_id,json=connectionId,proto3" json:"connection_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Operation) Reset()         { *m = Operation{} }
+func (m *Operation) String() string { return proto.CompactTextString(m) }
+func (*Operation) ProtoMessage()    {}
+func (*Operation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+func (m *Operation) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Operation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Operation.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	dAtA[offset] = uint8(v)
-	return base
 }
-func (m *Event) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Operation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Operation.Merge(m, src)
+}
+func (m *Operation) XXX_Size() int {
+	return m.Size()
+}
+func (m *Operation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Operation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Operation proto.InternalMessageInfo
+
+func (m *Operation) GetUserID() string {
+	if m != nil {
+		return m.UserID
 	}
-	var l int
-	_ = l
-	if m.Type != 0 {
-		n += 1 + sovApi(uint64(m.Type))
+	return ""
+}
+
+func (m *Operation) GetType() OpType {
+	if m != nil {
+		return m.Type
 	}
-	l = len(m.Event)
-	if l > 0 {
-		n += 1 + l + sovApi(uint64(l))
+	return OpType_INSERT
+}
+
+func (m *Operation) GetIndex() int32 {
+	if m != nil {
+		return m.Index
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	return 0
+}
+
+func (m *Operation) GetLen() int32 {
+	if m != nil {
+		return m.Len
 	}
-	return n
+	return 0
 }
 
-func (m *Init) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *Operation) GetText() string {
+	if m != nil {
+		return m.Text
 	}
-	var l int
-	_ = l
-	l = len(m.DocumentName)
-	if l > 0 {
-		n += 1 + l + sovApi(uint64(l))
+	return ""
+}
+
+func (m *Operation) GetVersion() int32 {
+	if m != nil {
+		return m.Version
 	}
-	l = len(m.Text)
-	if l > 0 {
-		n += 1 + l + sovApi(uint64(l))
+	return 0
+}
+
+func (m *Operation) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	if m.LastVersion != 0 {
-		n += 1 + sovApi(uint64(m.LastVersion))
+	return ""
+}
+
+func (m *Operation) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	return 0
+}
+
+func (m *Operation) GetConnectionId() string {
+	if m != nil {
+		return m.ConnectionId
 	}
-	return n
+	return ""
 }
 
-func (m *Operation) Size() (n int) {
-	if m == nil {
-		return 0
+// OperationBatch is an ordered list of operations applied atomically at a
+// single version, each one to the text left by the previous one. When sent by
+// a client, version is the version all of them are based on and id works like
+// Operation.id, sharing its scope. The server sends revisions made of more
+// than one operation as a batch, with version and checksum set like for a
+// single Operation. A batch holds at least one operation. Operations that
+// cancel each other out make no revision, they are acked at the current
+// version.
+type OperationBatch struct {
+	Operations           []*Operation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	Version              int32        `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Id                   string       `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	Checksum             uint32       `protobuf:"varint,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *OperationBatch) Reset()         { *m = OperationBatch{} }
+func (m *OperationBatch) String() string { return proto.CompactTextString(m) }
+func (*OperationBatch) ProtoMessage()    {}
+func (*OperationBatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+func (m *OperationBatch) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OperationBatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OperationBatch.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	var l int
-	_ = l
-	l = len(m.UserID)
-	if l > 0 {
-		n += 1 + l + sovApi(uint64(l))
+}
+func (m *OperationBatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperationBatch.Merge(m, src)
+}
+func (m *OperationBatch) XXX_Size() int {
+	return m.Size()
+}
+func (m *OperationBatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperationBatch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperationBatch proto.InternalMessageInfo
+
+func (m *OperationBatch) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
 	}
-	if m.Type != 0 {
-		n += 1 + sovApi(uint64(m.Type))
+	return nil
+}
+
+func (m *OperationBatch) GetVersion() int32 {
+	if m != nil {
+		return m.Version
 	}
-	if m.Index != 0 {
-		n += 1 + sovApi(uint64(m.Index))
+	return 0
+}
+
+func (m *OperationBatch) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	if m.Len != 0 {
-		n += 1 + sovApi(uint64(m.Len))
+	return ""
+}
+
+func (m *OperationBatch) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
 	}
-	l = len(m.Text)
-	if l > 0 {
-		n += 1 + l + sovApi(uint64(l))
+	return 0
+}
+
+// Undo asks the server to revert the latest revision of the user that is not
+// undone yet, on any of its connections. The server keeps what every user can
+// undo and redo for as long as the document stays open, and reverts the
+// revision as a new one, transformed against everything applied since. The
+// requesting connection gets it as an OPERATION or OPERATION_BATCH answering
+// the request, everyone else like any other revision. A NOTHING_TO_UNDO error
+// answers it if there is nothing left to undo. On the v1 subprotocols the
+// event field of an UNDO or REDO Event may be left out.
+type Undo struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Undo) Reset()         { *m = Undo{} }
+func (m *Undo) String() string { return proto.CompactTextString(m) }
+func (*Undo) ProtoMessage()    {}
+func (*Undo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+func (m *Undo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Undo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Undo.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.Version != 0 {
-		n += 1 + sovApi(uint64(m.Version))
+}
+func (m *Undo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Undo.Merge(m, src)
+}
+func (m *Undo) XXX_Size() int {
+	return m.Size()
+}
+func (m *Undo) XXX_DiscardUnknown() {
+	xxx_messageInfo_Undo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Undo proto.InternalMessageInfo
+
+// Redo asks the server to revert the latest undo of the user, as long as the
+// user made no other revision since. It works like Undo.
+type Redo struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Redo) Reset()         { *m = Redo{} }
+func (m *Redo) String() string { return proto.CompactTextString(m) }
+func (*Redo) ProtoMessage()    {}
+func (*Redo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+func (m *Redo) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Redo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Redo.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+}
+func (m *Redo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Redo.Merge(m, src)
+}
+func (m *Redo) XXX_Size() int {
+	return m.Size()
+}
+func (m *Redo) XXX_DiscardUnknown() {
+	xxx_messageInfo_Redo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Redo proto.InternalMessageInfo
+
+// OperationAck confirms a client operation or batch. operations holds them as
+// the server applied them, after transforming them against the changes the
+// client had not seen yet and merging adjacent ones; an operation may be split
+// into several parts or disappear. Operations that were already applied are
+// acknowledged again with the version they were applied at. checksum is the
+// checksum of the document text at last_version.
+type OperationAck struct {
+	LastVersion          int32        `protobuf:"varint,1,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
+	Operations           []*Operation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Checksum             uint32       `protobuf:"varint,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *OperationAck) Reset()         { *m = OperationAck{} }
+func (m *OperationAck) String() string { return proto.CompactTextString(m) }
+func (*OperationAck) ProtoMessage()    {}
+func (*OperationAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+func (m *OperationAck) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *OperationAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_OperationAck.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *OperationAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OperationAck.Merge(m, src)
+}
+func (m *OperationAck) XXX_Size() int {
+	return m.Size()
+}
+func (m *OperationAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_OperationAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OperationAck proto.InternalMessageInfo
+
+func (m *OperationAck) GetLastVersion() int32 {
+	if m != nil {
+		return m.LastVersion
+	}
+	return 0
+}
+
+func (m *OperationAck) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *OperationAck) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+// Checksum is the CRC-32 (IEEE) of the UTF-8 encoded document text at version.
+// Clients send it to have the server check their state, the server answers
+// with RESYNC if it does not match.
+type Checksum struct {
+	Version              int32    `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Checksum             uint32   `protobuf:"varint,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Checksum) Reset()         { *m = Checksum{} }
+func (m *Checksum) String() string { return proto.CompactTextString(m) }
+func (*Checksum) ProtoMessage()    {}
+func (*Checksum) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+func (m *Checksum) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Checksum) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Checksum.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Checksum) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Checksum.Merge(m, src)
+}
+func (m *Checksum) XXX_Size() int {
+	return m.Size()
+}
+func (m *Checksum) XXX_DiscardUnknown() {
+	xxx_messageInfo_Checksum.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Checksum proto.InternalMessageInfo
+
+func (m *Checksum) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Checksum) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+// Resync replaces the whole client state. The client has to drop its
+// unacknowledged operations and continue from last_version.
+type Resync struct {
+	Text                 string   `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	LastVersion          int32    `protobuf:"varint,2,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
+	Checksum             uint32   `protobuf:"varint,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Resync) Reset()         { *m = Resync{} }
+func (m *Resync) String() string { return proto.CompactTextString(m) }
+func (*Resync) ProtoMessage()    {}
+func (*Resync) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+func (m *Resync) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Resync) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Resync.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Resync) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Resync.Merge(m, src)
+}
+func (m *Resync) XXX_Size() int {
+	return m.Size()
+}
+func (m *Resync) XXX_DiscardUnknown() {
+	xxx_messageInfo_Resync.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Resync proto.InternalMessageInfo
+
+func (m *Resync) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+func (m *Resync) GetLastVersion() int32 {
+	if m != nil {
+		return m.LastVersion
+	}
+	return 0
+}
+
+func (m *Resync) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+// Error is sent when the server can't process something a client sent.
+// DOCUMENT_DELETED is also sent on its own when the document gets deleted,
+// right before the server disconnects the client.
+// operation is the offending operation, if any. resync is set when the client
+// state has clearly diverged from the server, in which case a RESYNC event
+// follows.
+type Error struct {
+	Code                 ErrorCode  `protobuf:"varint,1,opt,name=code,proto3,enum=api_pb.ErrorCode" json:"code,omitempty"`
+	Message              string     `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Operation            *Operation `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	Resync               bool       `protobuf:"varint,4,opt,name=resync,proto3" json:"resync,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Error) Reset()         { *m = Error{} }
+func (m *Error) String() string { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()    {}
+func (*Error) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+func (m *Error) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Error) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Error.Merge(m, src)
+}
+func (m *Error) XXX_Size() int {
+	return m.Size()
+}
+func (m *Error) XXX_DiscardUnknown() {
+	xxx_messageInfo_Error.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Error proto.InternalMessageInfo
+
+func (m *Error) GetCode() ErrorCode {
+	if m != nil {
+		return m.Code
+	}
+	return ErrorCode_INTERNAL
+}
+
+func (m *Error) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Error) GetOperation() *Operation {
+	if m != nil {
+		return m.Operation
+	}
+	return nil
+}
+
+func (m *Error) GetResync() bool {
+	if m != nil {
+		return m.Resync
+	}
+	return false
+}
+
+// SyncRequest uploads operations a client made while offline, one after
+// another, starting from version. They are committed as a single revision,
+// like an OperationBatch. id makes retries safe: operations with an id already
+// committed by the same user are not applied again. Positions are counted in
+// position_unit. A request with no operations only fetches what the client
+// missed.
+type SyncRequest struct {
+	Version              int32        `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Operations           []*Operation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Id                   string       `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	PositionUnit         PositionUnit `protobuf:"varint,4,opt,name=position_unit,json=positionUnit,proto3,enum=api_pb.PositionUnit" json:"position_unit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SyncRequest) Reset()         { *m = SyncRequest{} }
+func (m *SyncRequest) String() string { return proto.CompactTextString(m) }
+func (*SyncRequest) ProtoMessage()    {}
+func (*SyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+func (m *SyncRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SyncRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncRequest.Merge(m, src)
+}
+func (m *SyncRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncRequest proto.InternalMessageInfo
+
+func (m *SyncRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *SyncRequest) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *SyncRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SyncRequest) GetPositionUnit() PositionUnit {
+	if m != nil {
+		return m.PositionUnit
+	}
+	return PositionUnit_CODE_POINTS
+}
+
+// SyncResponse answers a SyncRequest. operations are the uploaded operations
+// as committed in last_version and missed the ones committed by others between
+// the version of the request and last_version, in order. When those are no
+// longer kept, missed is empty and text and last_version are the current text
+// and version of the document instead.
+type SyncResponse struct {
+	LastVersion          int32        `protobuf:"varint,1,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"`
+	Operations           []*Operation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Missed               []*Operation `protobuf:"bytes,3,rep,name=missed,proto3" json:"missed,omitempty"`
+	Checksum             uint32       `protobuf:"varint,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Text                 string       `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *SyncResponse) Reset()         { *m = SyncResponse{} }
+func (m *SyncResponse) String() string { return proto.CompactTextString(m) }
+func (*SyncResponse) ProtoMessage()    {}
+func (*SyncResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
+}
+func (m *SyncResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SyncResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SyncResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SyncResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncResponse.Merge(m, src)
+}
+func (m *SyncResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SyncResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncResponse proto.InternalMessageInfo
+
+func (m *SyncResponse) GetLastVersion() int32 {
+	if m != nil {
+		return m.LastVersion
+	}
+	return 0
+}
+
+func (m *SyncResponse) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *SyncResponse) GetMissed() []*Operation {
+	if m != nil {
+		return m.Missed
+	}
+	return nil
+}
+
+func (m *SyncResponse) GetChecksum() uint32 {
+	if m != nil {
+		return m.Checksum
+	}
+	return 0
+}
+
+func (m *SyncResponse) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+// CrdtId identifies a character of a CRDT document, or a deletion: the site
+// that made it and a Lamport clock. Sites pick ids unique to them, and every
+// new clock of a site is higher than any clock it has seen in the document.
+type CrdtId struct {
+	Site                 string   `protobuf:"bytes,1,opt,name=site,proto3" json:"site,omitempty"`
+	Clock                uint64   `protobuf:"varint,2,opt,name=clock,proto3" json:"clock,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CrdtId) Reset()         { *m = CrdtId{} }
+func (m *CrdtId) String() string { return proto.CompactTextString(m) }
+func (*CrdtId) ProtoMessage()    {}
+func (*CrdtId) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+func (m *CrdtId) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CrdtId) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CrdtId.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CrdtId) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrdtId.Merge(m, src)
+}
+func (m *CrdtId) XXX_Size() int {
+	return m.Size()
+}
+func (m *CrdtId) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrdtId.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrdtId proto.InternalMessageInfo
+
+func (m *CrdtId) GetSite() string {
+	if m != nil {
+		return m.Site
+	}
+	return ""
+}
+
+func (m *CrdtId) GetClock() uint64 {
+	if m != nil {
+		return m.Clock
+	}
+	return 0
+}
+
+// CrdtInsert inserts text right after the character origin, or at the start of
+// the document if origin is unset. The characters get consecutive clocks
+// starting with the one of the op.
+type CrdtInsert struct {
+	Origin               *CrdtId  `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Text                 string   `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CrdtInsert) Reset()         { *m = CrdtInsert{} }
+func (m *CrdtInsert) String() string { return proto.CompactTextString(m) }
+func (*CrdtInsert) ProtoMessage()    {}
+func (*CrdtInsert) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
+}
+func (m *CrdtInsert) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CrdtInsert) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CrdtInsert.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CrdtInsert) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrdtInsert.Merge(m, src)
+}
+func (m *CrdtInsert) XXX_Size() int {
+	return m.Size()
+}
+func (m *CrdtInsert) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrdtInsert.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrdtInsert proto.InternalMessageInfo
+
+func (m *CrdtInsert) GetOrigin() *CrdtId {
+	if m != nil {
+		return m.Origin
+	}
+	return nil
+}
+
+func (m *CrdtInsert) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+// CrdtDelete deletes len characters inserted by one op, starting at target.
+type CrdtDelete struct {
+	Target               *CrdtId  `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Len                  uint32   `protobuf:"varint,2,opt,name=len,proto3" json:"len,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CrdtDelete) Reset()         { *m = CrdtDelete{} }
+func (m *CrdtDelete) String() string { return proto.CompactTextString(m) }
+func (*CrdtDelete) ProtoMessage()    {}
+func (*CrdtDelete) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
+}
+func (m *CrdtDelete) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CrdtDelete) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CrdtDelete.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CrdtDelete) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrdtDelete.Merge(m, src)
+}
+func (m *CrdtDelete) XXX_Size() int {
+	return m.Size()
+}
+func (m *CrdtDelete) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrdtDelete.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrdtDelete proto.InternalMessageInfo
+
+func (m *CrdtDelete) GetTarget() *CrdtId {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (m *CrdtDelete) GetLen() uint32 {
+	if m != nil {
+		return m.Len
+	}
+	return 0
+}
+
+type CrdtOp struct {
+	Id *CrdtId `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Op:
+	//	*CrdtOp_Insert
+	//	*CrdtOp_Delete
+	Op                   isCrdtOp_Op `protobuf_oneof:"op"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *CrdtOp) Reset()         { *m = CrdtOp{} }
+func (m *CrdtOp) String() string { return proto.CompactTextString(m) }
+func (*CrdtOp) ProtoMessage()    {}
+func (*CrdtOp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
+}
+func (m *CrdtOp) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CrdtOp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CrdtOp.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CrdtOp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrdtOp.Merge(m, src)
+}
+func (m *CrdtOp) XXX_Size() int {
+	return m.Size()
+}
+func (m *CrdtOp) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrdtOp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrdtOp proto.InternalMessageInfo
+
+type isCrdtOp_Op interface {
+	isCrdtOp_Op()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type CrdtOp_Insert struct {
+	Insert *CrdtInsert `protobuf:"bytes,2,opt,name=insert,proto3,oneof" json:"insert,omitempty"`
+}
+type CrdtOp_Delete struct {
+	Delete *CrdtDelete `protobuf:"bytes,3,opt,name=delete,proto3,oneof" json:"delete,omitempty"`
+}
+
+func (*CrdtOp_Insert) isCrdtOp_Op() {}
+func (*CrdtOp_Delete) isCrdtOp_Op() {}
+
+func (m *CrdtOp) GetOp() isCrdtOp_Op {
+	if m != nil {
+		return m.Op
+	}
+	return nil
+}
+
+func (m *CrdtOp) GetId() *CrdtId {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *CrdtOp) GetInsert() *CrdtInsert {
+	if x, ok := m.GetOp().(*CrdtOp_Insert); ok {
+		return x.Insert
+	}
+	return nil
+}
+
+func (m *CrdtOp) GetDelete() *CrdtDelete {
+	if x, ok := m.GetOp().(*CrdtOp_Delete); ok {
+		return x.Delete
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*CrdtOp) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*CrdtOp_Insert)(nil),
+		(*CrdtOp_Delete)(nil),
+	}
+}
+
+// CrdtUpdate is a list of ops on a CRDT document, each one depending only on
+// ops before it or already sent. Clients send the ops they make, the server
+// relays the ops of others. Ops the server already has are ignored, so
+// updates can safely be sent again after a reconnect. A client that gets an op
+// depending on characters it doesn't know about missed some and should sync.
+type CrdtUpdate struct {
+	Ops                  []*CrdtOp `protobuf:"bytes,1,rep,name=ops,proto3" json:"ops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CrdtUpdate) Reset()         { *m = CrdtUpdate{} }
+func (m *CrdtUpdate) String() string { return proto.CompactTextString(m) }
+func (*CrdtUpdate) ProtoMessage()    {}
+func (*CrdtUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
+}
+func (m *CrdtUpdate) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CrdtUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CrdtUpdate.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CrdtUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrdtUpdate.Merge(m, src)
+}
+func (m *CrdtUpdate) XXX_Size() int {
+	return m.Size()
+}
+func (m *CrdtUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrdtUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrdtUpdate proto.InternalMessageInfo
+
+func (m *CrdtUpdate) GetOps() []*CrdtOp {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+// CrdtSync catches up the two sides of a CRDT document. state_vector is the
+// highest clock of every site the sender has seen. A client sends it with no
+// ops after Init and on reconnect, and the server answers with its own state
+// vector and the ops the client is missing; the client then sends the ops the
+// server is missing as a CrdtUpdate. The server also answers every CrdtUpdate
+// with a CrdtSync holding just its state vector, confirming the ops are stored.
+// A CrdtUpdate with ops depending on characters the server hasn't seen fails
+// with CRDT_SYNC_REQUIRED, the client sends a CrdtSync to catch up and then
+// its ops again.
+type CrdtSync struct {
+	StateVector          map[string]uint64 `protobuf:"bytes,1,rep,name=state_vector,json=stateVector,proto3" json:"state_vector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	Ops                  []*CrdtOp         `protobuf:"bytes,2,rep,name=ops,proto3" json:"ops,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CrdtSync) Reset()         { *m = CrdtSync{} }
+func (m *CrdtSync) String() string { return proto.CompactTextString(m) }
+func (*CrdtSync) ProtoMessage()    {}
+func (*CrdtSync) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
+}
+func (m *CrdtSync) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CrdtSync) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CrdtSync.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CrdtSync) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CrdtSync.Merge(m, src)
+}
+func (m *CrdtSync) XXX_Size() int {
+	return m.Size()
+}
+func (m *CrdtSync) XXX_DiscardUnknown() {
+	xxx_messageInfo_CrdtSync.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CrdtSync proto.InternalMessageInfo
+
+func (m *CrdtSync) GetStateVector() map[string]uint64 {
+	if m != nil {
+		return m.StateVector
+	}
+	return nil
+}
+
+func (m *CrdtSync) GetOps() []*CrdtOp {
+	if m != nil {
+		return m.Ops
+	}
+	return nil
+}
+
+// Document is the info of a document.
+type Document struct {
+	Id                   string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Author               string       `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Mode                 DocumentMode `protobuf:"varint,4,opt,name=mode,proto3,enum=api_pb.DocumentMode" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Document) Reset()         { *m = Document{} }
+func (m *Document) String() string { return proto.CompactTextString(m) }
+func (*Document) ProtoMessage()    {}
+func (*Document) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+func (m *Document) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Document) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Document.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Document) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Document.Merge(m, src)
+}
+func (m *Document) XXX_Size() int {
+	return m.Size()
+}
+func (m *Document) XXX_DiscardUnknown() {
+	xxx_messageInfo_Document.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Document proto.InternalMessageInfo
+
+func (m *Document) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Document) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Document) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *Document) GetMode() DocumentMode {
+	if m != nil {
+		return m.Mode
+	}
+	return DocumentMode_OT
+}
+
+type ListDocumentsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDocumentsRequest) Reset()         { *m = ListDocumentsRequest{} }
+func (m *ListDocumentsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDocumentsRequest) ProtoMessage()    {}
+func (*ListDocumentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+}
+func (m *ListDocumentsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ListDocumentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ListDocumentsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ListDocumentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDocumentsRequest.Merge(m, src)
+}
+func (m *ListDocumentsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ListDocumentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDocumentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDocumentsRequest proto.InternalMessageInfo
+
+type ListDocumentsResponse struct {
+	Documents            []*Document `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListDocumentsResponse) Reset()         { *m = ListDocumentsResponse{} }
+func (m *ListDocumentsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDocumentsResponse) ProtoMessage()    {}
+func (*ListDocumentsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+}
+func (m *ListDocumentsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ListDocumentsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ListDocumentsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ListDocumentsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDocumentsResponse.Merge(m, src)
+}
+func (m *ListDocumentsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ListDocumentsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDocumentsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDocumentsResponse proto.InternalMessageInfo
+
+func (m *ListDocumentsResponse) GetDocuments() []*Document {
+	if m != nil {
+		return m.Documents
+	}
+	return nil
+}
+
+type CreateDocumentRequest struct {
+	Name                 string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Author               string       `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Mode                 DocumentMode `protobuf:"varint,3,opt,name=mode,proto3,enum=api_pb.DocumentMode" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *CreateDocumentRequest) Reset()         { *m = CreateDocumentRequest{} }
+func (m *CreateDocumentRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateDocumentRequest) ProtoMessage()    {}
+func (*CreateDocumentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+}
+func (m *CreateDocumentRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *CreateDocumentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_CreateDocumentRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *CreateDocumentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateDocumentRequest.Merge(m, src)
+}
+func (m *CreateDocumentRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *CreateDocumentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateDocumentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateDocumentRequest proto.InternalMessageInfo
+
+func (m *CreateDocumentRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateDocumentRequest) GetAuthor() string {
+	if m != nil {
+		return m.Author
+	}
+	return ""
+}
+
+func (m *CreateDocumentRequest) GetMode() DocumentMode {
+	if m != nil {
+		return m.Mode
+	}
+	return DocumentMode_OT
+}
+
+type DeleteDocumentRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteDocumentRequest) Reset()         { *m = DeleteDocumentRequest{} }
+func (m *DeleteDocumentRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteDocumentRequest) ProtoMessage()    {}
+func (*DeleteDocumentRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+}
+func (m *DeleteDocumentRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteDocumentRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteDocumentRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DeleteDocumentRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteDocumentRequest.Merge(m, src)
+}
+func (m *DeleteDocumentRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteDocumentRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteDocumentRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteDocumentRequest proto.InternalMessageInfo
+
+func (m *DeleteDocumentRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type DeleteDocumentResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteDocumentResponse) Reset()         { *m = DeleteDocumentResponse{} }
+func (m *DeleteDocumentResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteDocumentResponse) ProtoMessage()    {}
+func (*DeleteDocumentResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{28}
+}
+func (m *DeleteDocumentResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DeleteDocumentResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DeleteDocumentResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DeleteDocumentResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteDocumentResponse.Merge(m, src)
+}
+func (m *DeleteDocumentResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DeleteDocumentResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteDocumentResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteDocumentResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterEnum("api_pb.Capability", Capability_name, Capability_value)
+	proto.RegisterEnum("api_pb.PositionUnit", PositionUnit_name, PositionUnit_value)
+	proto.RegisterEnum("api_pb.OpType", OpType_name, OpType_value)
+	proto.RegisterEnum("api_pb.ErrorCode", ErrorCode_name, ErrorCode_value)
+	proto.RegisterEnum("api_pb.DocumentMode", DocumentMode_name, DocumentMode_value)
+	proto.RegisterEnum("api_pb.Event_EventType", Event_EventType_name, Event_EventType_value)
+	proto.RegisterType((*Event)(nil), "api_pb.Event")
+	proto.RegisterType((*Envelope)(nil), "api_pb.Envelope")
+	proto.RegisterType((*Hello)(nil), "api_pb.Hello")
+	proto.RegisterType((*Participant)(nil), "api_pb.Participant")
+	proto.RegisterType((*Cursor)(nil), "api_pb.Cursor")
+	proto.RegisterType((*Init)(nil), "api_pb.Init")
+	proto.RegisterType((*Resume)(nil), "api_pb.Resume")
+	proto.RegisterType((*Operation)(nil), "api_pb.Operation")
+	proto.RegisterType((*OperationBatch)(nil), "api_pb.OperationBatch")
+	proto.RegisterType((*Undo)(nil), "api_pb.Undo")
+	proto.RegisterType((*Redo)(nil), "api_pb.Redo")
+	proto.RegisterType((*OperationAck)(nil), "api_pb.OperationAck")
+	proto.RegisterType((*Checksum)(nil), "api_pb.Checksum")
+	proto.RegisterType((*Resync)(nil), "api_pb.Resync")
+	proto.RegisterType((*Error)(nil), "api_pb.Error")
+	proto.RegisterType((*SyncRequest)(nil), "api_pb.SyncRequest")
+	proto.RegisterType((*SyncResponse)(nil), "api_pb.SyncResponse")
+	proto.RegisterType((*CrdtId)(nil), "api_pb.CrdtId")
+	proto.RegisterType((*CrdtInsert)(nil), "api_pb.CrdtInsert")
+	proto.RegisterType((*CrdtDelete)(nil), "api_pb.CrdtDelete")
+	proto.RegisterType((*CrdtOp)(nil), "api_pb.CrdtOp")
+	proto.RegisterType((*CrdtUpdate)(nil), "api_pb.CrdtUpdate")
+	proto.RegisterType((*CrdtSync)(nil), "api_pb.CrdtSync")
+	proto.RegisterMapType((map[string]uint64)(nil), "api_pb.CrdtSync.StateVectorEntry")
+	proto.RegisterType((*Document)(nil), "api_pb.Document")
+	proto.RegisterType((*ListDocumentsRequest)(nil), "api_pb.ListDocumentsRequest")
+	proto.RegisterType((*ListDocumentsResponse)(nil), "api_pb.ListDocumentsResponse")
+	proto.RegisterType((*CreateDocumentRequest)(nil), "api_pb.CreateDocumentRequest")
+	proto.RegisterType((*DeleteDocumentRequest)(nil), "api_pb.DeleteDocumentRequest")
+	proto.RegisterType((*DeleteDocumentResponse)(nil), "api_pb.DeleteDocumentResponse")
+}
+
+func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
+
+var fileDescriptor_00212fb1f9d3bf1c = []byte{
+	// 2018 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xcf, 0x93, 0xdb, 0x4a,
+	0xf1, 0xb7, 0x6c, 0x59, 0xb6, 0xdb, 0x3f, 0x56, 0x99, 0x24, 0x1b, 0xd5, 0xd6, 0x37, 0x5b, 0xfb,
+	0xf4, 0xad, 0x07, 0xfb, 0x16, 0xde, 0x86, 0x84, 0x22, 0x40, 0xa8, 0xa2, 0xf0, 0xda, 0x4a, 0xac,
+	0x17, 0xaf, 0xe4, 0x8c, 0xe5, 0x50, 0x81, 0x83, 0x4a, 0x2b, 0x0d, 0x59, 0x11, 0xaf, 0xa4, 0x27,
+	0xc9, 0x5b, 0x6f, 0x6f, 0x5c, 0xa1, 0xb8, 0x70, 0xa3, 0x8a, 0x33, 0x07, 0xae, 0xef, 0xc6, 0x89,
+	0x2b, 0xdc, 0xf8, 0x13, 0xa8, 0x70, 0xa5, 0x38, 0x72, 0xa6, 0x66, 0x46, 0xb2, 0x25, 0xdb, 0xbb,
+	0x6f, 0x49, 0xd5, 0xbb, 0xec, 0x4e, 0x77, 0x7f, 0x7a, 0xd4, 0xdd, 0xd3, 0xdd, 0xd3, 0x1e, 0x68,
+	0x39, 0x91, 0x7f, 0x1c, 0xc5, 0x61, 0x1a, 0x22, 0xc9, 0x89, 0x7c, 0x3b, 0x3a, 0x53, 0xff, 0x5c,
+	0x85, 0xba, 0x76, 0x49, 0x82, 0x14, 0x7d, 0x0b, 0xc4, 0xf4, 0x2a, 0x22, 0x8a, 0x70, 0x20, 0x1c,
+	0xf6, 0x9e, 0x3c, 0x38, 0xe6, 0x80, 0x63, 0x26, 0xe4, 0x7f, 0xad, 0xab, 0x88, 0x60, 0x06, 0x42,
+	0xf7, 0xa0, 0x4e, 0x28, 0x4b, 0xa9, 0x1e, 0x08, 0x87, 0x1d, 0xcc, 0x09, 0xf5, 0x5f, 0x02, 0xb4,
+	0x96, 0x48, 0xd4, 0x04, 0x51, 0x37, 0x74, 0x4b, 0xae, 0xa0, 0x3b, 0xd0, 0x1d, 0x8c, 0x75, 0xcd,
+	0xb0, 0xec, 0xcf, 0x4c, 0xdd, 0xd0, 0x86, 0xb2, 0x80, 0x76, 0xa0, 0x9d, 0xb1, 0x5e, 0xcd, 0x74,
+	0x4b, 0xae, 0xa2, 0x2e, 0xb4, 0xcc, 0x89, 0x86, 0xfb, 0x96, 0x6e, 0x1a, 0x72, 0x8d, 0xaa, 0x2c,
+	0x49, 0xbb, 0x3f, 0x78, 0x29, 0x8b, 0x08, 0x40, 0x1a, 0xcc, 0xf0, 0xd4, 0xc4, 0x72, 0x9d, 0xae,
+	0xb1, 0x36, 0x9d, 0x9d, 0x6a, 0xb2, 0x84, 0x5a, 0x50, 0xd7, 0x30, 0x36, 0xb1, 0xdc, 0x40, 0x1d,
+	0x68, 0x0e, 0x46, 0xda, 0xe0, 0xe5, 0x74, 0x76, 0x2a, 0x37, 0x33, 0xd0, 0x1b, 0x63, 0x20, 0xb7,
+	0xd0, 0x5d, 0xd8, 0x59, 0xed, 0x77, 0xd2, 0xb7, 0x06, 0x23, 0x19, 0xa8, 0xe6, 0x48, 0x1b, 0x8f,
+	0x4d, 0xb9, 0x4d, 0x3f, 0x3f, 0xc0, 0x43, 0xcb, 0x66, 0xf0, 0x0e, 0x33, 0x8f, 0x92, 0xb3, 0xc9,
+	0xb0, 0x6f, 0x69, 0x72, 0x97, 0x3a, 0x33, 0x33, 0x86, 0xa6, 0xdc, 0xa3, 0x2b, 0xac, 0x0d, 0x4d,
+	0x79, 0x47, 0xfd, 0x93, 0x04, 0x4d, 0x2d, 0xb8, 0x24, 0xf3, 0x30, 0x22, 0xe8, 0x21, 0x40, 0x4c,
+	0x3e, 0x5f, 0x90, 0x24, 0xb5, 0x7d, 0x8f, 0x05, 0xb1, 0x85, 0x5b, 0x19, 0x47, 0xf7, 0x90, 0x0a,
+	0xa2, 0x1f, 0xf8, 0x3c, 0x5e, 0xed, 0x27, 0x9d, 0x3c, 0xba, 0x7a, 0xe0, 0xa7, 0xa3, 0x0a, 0x66,
+	0x32, 0xf4, 0x0c, 0xba, 0xee, 0xdc, 0x27, 0x41, 0x6a, 0xff, 0x32, 0xf4, 0x03, 0xe2, 0x29, 0x35,
+	0x06, 0xbe, 0x9b, 0x83, 0x27, 0x4e, 0x9c, 0xfa, 0xae, 0x1f, 0x39, 0x01, 0xd5, 0xe9, 0x70, 0xec,
+	0x67, 0x0c, 0x8a, 0x9e, 0x42, 0x3b, 0xd3, 0xfd, 0x7c, 0xe1, 0xa7, 0x8a, 0x78, 0x93, 0x26, 0x70,
+	0xe4, 0xab, 0x85, 0x9f, 0xa2, 0xc7, 0xd0, 0x0a, 0x23, 0x12, 0x3b, 0xa9, 0x1f, 0x06, 0x4a, 0x9d,
+	0x69, 0xdd, 0xc9, 0xb5, 0xcc, 0x5c, 0x30, 0xaa, 0xe0, 0x15, 0x0a, 0xfd, 0x08, 0xba, 0x4b, 0xc2,
+	0x76, 0xdc, 0x77, 0x8a, 0xc4, 0xd4, 0xee, 0x6d, 0xa8, 0xf5, 0xdd, 0x77, 0xd4, 0xce, 0xb0, 0x40,
+	0xa3, 0x43, 0x90, 0xdc, 0x45, 0x9c, 0x84, 0xb1, 0xd2, 0x60, 0x5a, 0xbd, 0x5c, 0x6b, 0xc0, 0xb8,
+	0xa3, 0x0a, 0xce, 0xe4, 0x14, 0x19, 0x93, 0x64, 0x71, 0x41, 0x94, 0x66, 0x19, 0x89, 0x19, 0x97,
+	0x22, 0xb9, 0x1c, 0x7d, 0x0c, 0x75, 0x12, 0xc7, 0x61, 0xac, 0xb4, 0x18, 0xb0, 0xbb, 0x4c, 0x5d,
+	0xca, 0x1c, 0x55, 0x30, 0x97, 0xa2, 0x63, 0x68, 0xba, 0xe7, 0xc4, 0x7d, 0x97, 0x2c, 0x2e, 0x14,
+	0x60, 0x48, 0x79, 0xf9, 0xf1, 0x8c, 0x3f, 0xaa, 0xe0, 0x25, 0x26, 0x33, 0xe0, 0x2a, 0x70, 0x95,
+	0xf6, 0x86, 0x01, 0x57, 0x81, 0x9b, 0x19, 0x70, 0x15, 0xb8, 0xa8, 0x0f, 0x3b, 0xab, 0x88, 0x9c,
+	0x39, 0xa9, 0x7b, 0xae, 0x74, 0x98, 0xca, 0xee, 0x46, 0x4c, 0x4e, 0xa8, 0x74, 0x54, 0xc1, 0xbd,
+	0xb0, 0xc4, 0xa1, 0x3e, 0x9c, 0x93, 0xf9, 0x3c, 0x54, 0xba, 0x65, 0x1f, 0x46, 0x94, 0x49, 0x7d,
+	0x60, 0x52, 0xf4, 0x08, 0x5a, 0x6e, 0xec, 0xa5, 0x36, 0x33, 0xab, 0xb7, 0xe6, 0x44, 0xec, 0xa5,
+	0x53, 0x6e, 0x58, 0xd3, 0xcd, 0xd6, 0xe8, 0x7b, 0xd0, 0x66, 0x0a, 0x8b, 0xc8, 0x73, 0x52, 0xa2,
+	0xec, 0x30, 0x15, 0x54, 0x54, 0x99, 0x31, 0x09, 0x4b, 0x8b, 0x25, 0x45, 0xd3, 0x75, 0x11, 0x78,
+	0xa1, 0x22, 0x97, 0xd3, 0x75, 0x16, 0x78, 0xd4, 0x18, 0x26, 0xa3, 0x98, 0x98, 0x78, 0xa1, 0x72,
+	0xa7, 0x8c, 0xc1, 0x84, 0x63, 0xa8, 0xec, 0xa4, 0x91, 0xf5, 0x09, 0xf5, 0x8f, 0x02, 0xd4, 0x99,
+	0x2f, 0xe8, 0x13, 0x90, 0x59, 0x0b, 0x72, 0xc3, 0xb9, 0x7d, 0x49, 0xe2, 0x84, 0x26, 0x1e, 0x2d,
+	0x97, 0x3a, 0xde, 0xc9, 0xf9, 0xaf, 0x39, 0x1b, 0xfd, 0x10, 0xba, 0x51, 0x98, 0xf8, 0x2c, 0xac,
+	0x8b, 0xbc, 0x7a, 0x7a, 0xab, 0x4c, 0x9b, 0x64, 0xc2, 0x59, 0xe0, 0xa7, 0xb8, 0x13, 0x15, 0x28,
+	0xf4, 0x14, 0x3a, 0xae, 0x13, 0x39, 0x67, 0xfe, 0xdc, 0x4f, 0x7d, 0x92, 0x28, 0xb5, 0x83, 0xda,
+	0x61, 0xaf, 0xe0, 0x78, 0x2e, 0xbb, 0xc2, 0x25, 0x9c, 0xba, 0x80, 0x76, 0xa1, 0x58, 0xd0, 0x03,
+	0x68, 0x2c, 0x12, 0x12, 0xaf, 0x4a, 0x5a, 0xa2, 0xa4, 0xee, 0x21, 0x04, 0x62, 0xe0, 0x5c, 0x10,
+	0x66, 0x51, 0x0b, 0xb3, 0x35, 0x6d, 0x8a, 0x6e, 0x38, 0x0f, 0x63, 0x56, 0xb7, 0x2d, 0xcc, 0x09,
+	0xf4, 0xff, 0xd0, 0x75, 0xc3, 0x20, 0x20, 0x2e, 0x73, 0xc3, 0xf7, 0x58, 0x6d, 0xb6, 0x70, 0x67,
+	0xc5, 0xd4, 0x3d, 0xf5, 0xb7, 0x02, 0x48, 0xbc, 0x02, 0xae, 0xff, 0xe4, 0x2e, 0x48, 0x4e, 0xe0,
+	0x9e, 0x87, 0x31, 0xfb, 0x68, 0x1d, 0x67, 0x14, 0x35, 0xe5, 0x9c, 0x38, 0xbc, 0x5b, 0xd4, 0x31,
+	0x5b, 0x23, 0x05, 0x1a, 0x79, 0x6c, 0x45, 0xc6, 0xce, 0xc9, 0x4d, 0x73, 0xea, 0x5b, 0xcc, 0xf9,
+	0xb2, 0x06, 0x22, 0x6d, 0x4d, 0x14, 0xed, 0x85, 0xee, 0xe2, 0x82, 0x36, 0x16, 0xe6, 0x2f, 0x37,
+	0xa9, 0x93, 0x33, 0x0d, 0xea, 0x37, 0x02, 0x31, 0x25, 0x5f, 0xa4, 0x79, 0x2c, 0xe8, 0x1a, 0x7d,
+	0x04, 0x9d, 0xb9, 0x93, 0xa4, 0xcb, 0x13, 0xe6, 0xc6, 0xb5, 0x29, 0xef, 0xda, 0xd3, 0x15, 0x6f,
+	0x7d, 0xba, 0xdf, 0x87, 0x4e, 0xb4, 0x3a, 0xa5, 0x44, 0xa9, 0x1f, 0xd4, 0xae, 0x69, 0x77, 0xb8,
+	0x04, 0x44, 0x87, 0xd0, 0xe0, 0xed, 0x25, 0x51, 0xa4, 0x83, 0x5a, 0xb1, 0xa8, 0x79, 0xf4, 0x71,
+	0x2e, 0xde, 0x8c, 0x53, 0x63, 0x33, 0x4e, 0x5b, 0x73, 0xb9, 0xb9, 0x3d, 0x97, 0xd7, 0x13, 0xb2,
+	0x75, 0xbb, 0x84, 0x44, 0x87, 0x20, 0x5e, 0x84, 0x1e, 0x51, 0xa0, 0x1c, 0x9c, 0x61, 0x76, 0x00,
+	0xa7, 0xa1, 0x47, 0x30, 0x43, 0xa8, 0x7f, 0xab, 0x82, 0xc4, 0x7b, 0xe3, 0x46, 0xf4, 0x85, 0xcd,
+	0xe8, 0x3f, 0x06, 0x58, 0xb6, 0xa0, 0x44, 0xa9, 0x1e, 0xd4, 0xb6, 0x76, 0x7e, 0x5c, 0x00, 0x6d,
+	0x44, 0xbd, 0xf6, 0x01, 0x51, 0x17, 0xff, 0xc7, 0xa8, 0xd7, 0x6f, 0x19, 0x75, 0xe9, 0x76, 0x51,
+	0x6f, 0xdc, 0xb2, 0x0d, 0xfc, 0x5b, 0x80, 0xd6, 0x32, 0x08, 0xb4, 0xf2, 0x58, 0x0d, 0x0e, 0x4b,
+	0x15, 0x39, 0xa4, 0x1d, 0x90, 0x8d, 0x4c, 0xbc, 0x2d, 0xf5, 0x56, 0xd1, 0x2b, 0x4f, 0x4a, 0x7e,
+	0xe0, 0x91, 0x2f, 0xb2, 0x0a, 0xe0, 0x04, 0x92, 0xa1, 0x36, 0x27, 0x79, 0x6d, 0xd2, 0xe5, 0xb2,
+	0x88, 0xea, 0x85, 0x22, 0x2a, 0x54, 0xb1, 0x54, 0xae, 0xe2, 0x1e, 0x54, 0x97, 0x29, 0x59, 0xf5,
+	0x3d, 0xb4, 0x57, 0xb8, 0xdb, 0x68, 0x02, 0x76, 0x0b, 0xf7, 0xd8, 0x46, 0x4c, 0x5b, 0x5b, 0x2a,
+	0xfe, 0xd7, 0x02, 0xf4, 0xca, 0x97, 0xd4, 0x5a, 0x86, 0x08, 0xb7, 0xc9, 0x90, 0x82, 0xc1, 0xd5,
+	0x6d, 0x06, 0xd7, 0xb6, 0x1a, 0x2c, 0x96, 0x0d, 0x56, 0x25, 0x10, 0xe9, 0x45, 0x43, 0xff, 0xd3,
+	0xcb, 0x44, 0xfd, 0x95, 0x00, 0x9d, 0xe2, 0x50, 0xf1, 0x35, 0xa5, 0x77, 0xd1, 0xa4, 0xda, 0x9a,
+	0x49, 0x3f, 0x81, 0x66, 0x3e, 0x23, 0x14, 0x9d, 0x14, 0xca, 0x4e, 0x16, 0x77, 0xa8, 0xae, 0xed,
+	0xf0, 0x73, 0x56, 0x9c, 0xf4, 0x4a, 0xce, 0x4f, 0x5a, 0xb8, 0xa1, 0x5d, 0x56, 0x37, 0x3d, 0xba,
+	0xc9, 0xbc, 0xdf, 0x09, 0x50, 0x67, 0xd3, 0x0e, 0xfa, 0x18, 0x44, 0x97, 0xb6, 0x0b, 0x3e, 0xc5,
+	0xdf, 0x29, 0x8d, 0x42, 0x03, 0xd6, 0x2b, 0xa8, 0x98, 0xfa, 0x70, 0x41, 0x92, 0xc4, 0x79, 0x9b,
+	0xdf, 0x60, 0x39, 0x49, 0x27, 0x8c, 0xd5, 0x40, 0x58, 0xbb, 0x66, 0x20, 0x2c, 0x8e, 0x83, 0xbb,
+	0xcb, 0x31, 0x89, 0x9e, 0x63, 0x33, 0x1f, 0x8a, 0xe8, 0x8d, 0xdf, 0xa6, 0x23, 0x08, 0xe6, 0x33,
+	0xf0, 0x0d, 0x61, 0xfb, 0x80, 0xb3, 0x5a, 0x4f, 0xa7, 0x0f, 0xbf, 0x4b, 0xd4, 0xbf, 0x08, 0xd0,
+	0xe1, 0x76, 0x26, 0x51, 0x18, 0x24, 0x5f, 0x57, 0xf3, 0xfc, 0x04, 0xa4, 0x0b, 0x3f, 0x49, 0xd8,
+	0x54, 0x7f, 0x0d, 0x3c, 0x03, 0xdc, 0x54, 0x1b, 0xdb, 0xda, 0x84, 0xfa, 0x04, 0x24, 0x3a, 0xc8,
+	0xf1, 0xa9, 0x24, 0xf1, 0xd3, 0xfc, 0x96, 0x66, 0x6b, 0x36, 0x95, 0xcc, 0x43, 0xf7, 0x1d, 0x3b,
+	0x68, 0x11, 0x73, 0x42, 0x1d, 0x01, 0x30, 0x9d, 0x20, 0x21, 0x71, 0x8a, 0xbe, 0x01, 0x52, 0x18,
+	0xfb, 0x6f, 0x7d, 0xee, 0x6c, 0xb1, 0x3f, 0xb3, 0x7d, 0x71, 0x26, 0xdd, 0x76, 0xd3, 0xab, 0xcf,
+	0xf9, 0x4e, 0x43, 0x32, 0x27, 0x29, 0xa1, 0x3b, 0xa5, 0x4e, 0xfc, 0x96, 0xa4, 0xd7, 0xed, 0xc4,
+	0xa5, 0x79, 0x03, 0xe4, 0x55, 0x42, 0x97, 0xea, 0x6f, 0x04, 0xee, 0x86, 0x19, 0xa1, 0x7d, 0x76,
+	0xba, 0xdb, 0x37, 0xa0, 0xa7, 0xfd, 0x6d, 0x90, 0x7c, 0x66, 0x78, 0xf6, 0x73, 0xaa, 0x34, 0xcf,
+	0x72, 0x97, 0xe8, 0x74, 0xce, 0x31, 0x14, 0xed, 0x31, 0xe3, 0x94, 0xda, 0x26, 0x9a, 0x9b, 0x4d,
+	0xd1, 0x1c, 0x73, 0x22, 0x42, 0x35, 0x8c, 0xd4, 0x63, 0xee, 0x54, 0x36, 0x0d, 0x1f, 0x40, 0x2d,
+	0x8c, 0xf2, 0x16, 0x58, 0x32, 0xc8, 0x8c, 0x30, 0x15, 0xa9, 0x5f, 0x0a, 0xd0, 0xcc, 0xe7, 0x6f,
+	0x34, 0x84, 0x4e, 0x92, 0x3a, 0x29, 0xb1, 0x2f, 0x89, 0x9b, 0x86, 0x71, 0xa6, 0xf7, 0xd1, 0xfa,
+	0x9c, 0x7e, 0x3c, 0xa5, 0xa0, 0xd7, 0x0c, 0xa3, 0x05, 0x69, 0x7c, 0x85, 0xdb, 0xc9, 0x8a, 0x93,
+	0x7f, 0xb4, 0x7a, 0xed, 0x47, 0xf7, 0x7e, 0x0c, 0xf2, 0xfa, 0x16, 0x34, 0xae, 0xef, 0xc8, 0x55,
+	0x96, 0x00, 0x74, 0x49, 0xcf, 0xff, 0xd2, 0x99, 0x2f, 0x48, 0x7e, 0xfe, 0x8c, 0x78, 0x56, 0xfd,
+	0x81, 0xa0, 0x46, 0xd0, 0xcc, 0xc7, 0x88, 0xac, 0xa0, 0x84, 0x65, 0x41, 0x6d, 0x9b, 0x6f, 0xe9,
+	0x00, 0xba, 0x48, 0xcf, 0x97, 0x03, 0x6e, 0x46, 0x2d, 0x47, 0x14, 0xf1, 0x2b, 0x47, 0x94, 0x5d,
+	0xb8, 0x37, 0xf6, 0x93, 0x34, 0x97, 0x24, 0x59, 0x6f, 0x50, 0x5f, 0xc0, 0xfd, 0x35, 0x7e, 0x56,
+	0x8b, 0xc7, 0xd0, 0xca, 0x47, 0xcd, 0x3c, 0xfe, 0xf2, 0xfa, 0xfe, 0x78, 0x05, 0x51, 0x2f, 0xe0,
+	0xfe, 0x20, 0x26, 0x4e, 0x4a, 0x96, 0xc2, 0xac, 0xfb, 0xe4, 0xfe, 0x08, 0x5b, 0xfd, 0xa9, 0x6e,
+	0xf5, 0xa7, 0xf6, 0x95, 0xfe, 0x7c, 0x13, 0xee, 0xf3, 0x04, 0x5a, 0xff, 0xdc, 0x5a, 0x38, 0x55,
+	0x05, 0x76, 0xd7, 0x81, 0xdc, 0xc3, 0x23, 0x13, 0x60, 0x35, 0x85, 0xa0, 0x3d, 0xd8, 0x1d, 0xf4,
+	0x27, 0xfd, 0x13, 0x7d, 0xac, 0x5b, 0x6f, 0xec, 0x99, 0x31, 0x9d, 0x68, 0x03, 0xfd, 0xb9, 0xae,
+	0x0d, 0xe5, 0x0a, 0x7d, 0xdc, 0x60, 0x0f, 0x17, 0xba, 0xf1, 0x42, 0x16, 0x50, 0x1b, 0x1a, 0xfc,
+	0x35, 0x64, 0x2a, 0x57, 0xe9, 0x4b, 0xc7, 0x89, 0x6e, 0xf4, 0xf1, 0x1b, 0xb9, 0x76, 0x74, 0x04,
+	0x9d, 0x62, 0xb7, 0x63, 0x4f, 0x19, 0xe6, 0x50, 0xb3, 0x27, 0xa6, 0x6e, 0x58, 0x53, 0xb9, 0x42,
+	0x5f, 0x3d, 0x66, 0xd6, 0xf3, 0xc7, 0x4f, 0x65, 0xe1, 0xe8, 0x00, 0x24, 0x3e, 0xac, 0xd0, 0x1d,
+	0x74, 0x63, 0xaa, 0x61, 0xfa, 0x5c, 0x03, 0x20, 0x0d, 0xb5, 0xb1, 0x66, 0x69, 0xb2, 0x70, 0xf4,
+	0x1f, 0xfa, 0xa4, 0x93, 0x5f, 0x1e, 0xd4, 0x04, 0xdd, 0xb0, 0x34, 0x6c, 0xf4, 0xc7, 0x72, 0x85,
+	0xbe, 0xa9, 0x9c, 0xf6, 0xc7, 0xcf, 0x4d, 0x7c, 0xaa, 0x0d, 0x6d, 0xed, 0xb5, 0x66, 0x58, 0xb2,
+	0x80, 0xee, 0xc3, 0x9d, 0x99, 0x31, 0x9d, 0x4d, 0x26, 0x26, 0xb6, 0x96, 0xec, 0x2a, 0x65, 0xeb,
+	0xc6, 0xeb, 0xfe, 0x58, 0x1f, 0xda, 0xc5, 0x67, 0x1e, 0x19, 0x3a, 0xe6, 0xcc, 0xb2, 0xcd, 0xe7,
+	0x36, 0xee, 0x1b, 0x2f, 0x34, 0x59, 0xa4, 0x9b, 0xce, 0x8c, 0x97, 0x86, 0xf9, 0x53, 0xc3, 0x7e,
+	0xad, 0xe1, 0x29, 0x85, 0xd5, 0xa9, 0x76, 0x46, 0xd8, 0x03, 0xf3, 0x74, 0xd2, 0x1f, 0x58, 0xda,
+	0x50, 0x96, 0xd0, 0x03, 0xb8, 0xab, 0x1b, 0x8c, 0x61, 0xe9, 0x27, 0x63, 0xcd, 0xe6, 0x2f, 0x4a,
+	0x72, 0x83, 0x6e, 0x62, 0x98, 0x16, 0x8d, 0x94, 0x6d, 0x99, 0x36, 0x7b, 0xb8, 0x69, 0xa2, 0x7b,
+	0x20, 0x0f, 0xcd, 0xc1, 0xec, 0x94, 0x3e, 0x3a, 0x71, 0xff, 0x86, 0x72, 0x0b, 0xed, 0x02, 0x5a,
+	0x3e, 0xfc, 0xd8, 0x58, 0x7b, 0x35, 0xd3, 0xb1, 0x36, 0x94, 0xe1, 0xe8, 0x00, 0x3a, 0xc5, 0x03,
+	0x47, 0x12, 0x54, 0x4d, 0x1a, 0x9c, 0x26, 0x88, 0x14, 0x2f, 0x0b, 0x4f, 0xfe, 0x50, 0x85, 0xee,
+	0x20, 0x9c, 0xcf, 0x9d, 0xb3, 0x29, 0x89, 0x2f, 0x7d, 0x97, 0xd0, 0xc4, 0xd1, 0x3c, 0x3f, 0x45,
+	0xdd, 0xd2, 0xe3, 0xd9, 0x5e, 0x99, 0x3c, 0x14, 0xbe, 0x23, 0xa0, 0x31, 0x74, 0x4b, 0x09, 0x8f,
+	0xfe, 0x2f, 0xc7, 0x6c, 0xab, 0x8f, 0xbd, 0x87, 0xd7, 0x48, 0xb3, 0x2a, 0xe9, 0x43, 0xaf, 0x9c,
+	0xf5, 0xe8, 0xe1, 0xaa, 0x5f, 0x6c, 0xa9, 0x86, 0xbd, 0x8d, 0x1a, 0x42, 0x26, 0xf4, 0xca, 0x09,
+	0xba, 0xda, 0x62, 0x6b, 0x86, 0xef, 0xed, 0x5f, 0x27, 0xe6, 0x36, 0x9d, 0x3c, 0xfb, 0xeb, 0xfb,
+	0x7d, 0xe1, 0xef, 0xef, 0xf7, 0x85, 0x7f, 0xbc, 0xdf, 0x17, 0x7e, 0xff, 0xcf, 0xfd, 0xca, 0xcf,
+	0x0e, 0xdf, 0xfa, 0xe9, 0xf9, 0xe2, 0xec, 0xd8, 0x0d, 0x2f, 0x1e, 0x25, 0x89, 0xb3, 0xf8, 0xf4,
+	0x17, 0xbe, 0x9f, 0x3e, 0x72, 0xe7, 0xe1, 0xc2, 0x0b, 0xdd, 0xe4, 0x53, 0x27, 0xf2, 0x1f, 0xf1,
+	0x2d, 0xcf, 0x24, 0x36, 0xc6, 0x7f, 0xf7, 0xbf, 0x03, 0x00, 0xe9, 0x6b, 0xd3, 0x75, 0xb0, 0x14,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// CollabServiceClient is the client API for CollabService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CollabServiceClient interface {
+	// Edit joins a document and works like the websocket endpoint with the
+	// cloudocs.proto.v1 subprotocol: the server starts with Init or Resume,
+	// and the payloads of Events are encoded as protobuf. The document and
+	// user are given in the document-id and x-cloudocs-id metadata, and name,
+	// unit and version metadata work like the websocket query parameters.
+	Edit(ctx context.Context, opts ...grpc.CallOption) (CollabService_EditClient, error)
+	ListDocuments(ctx context.Context, in *ListDocumentsRequest, opts ...grpc.CallOption) (*ListDocumentsResponse, error)
+	CreateDocument(ctx context.Context, in *CreateDocumentRequest, opts ...grpc.CallOption) (*Document, error)
+	// DeleteDocument fails with NOT_FOUND if the document does not exist.
+	// Clients editing the document get a DOCUMENT_DELETED error and are
+	// disconnected.
+	DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*DeleteDocumentResponse, error)
+}
+
+type collabServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCollabServiceClient(cc *grpc.ClientConn) CollabServiceClient {
+	return &collabServiceClient{cc}
+}
+
+func (c *collabServiceClient) Edit(ctx context.Context, opts ...grpc.CallOption) (CollabService_EditClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CollabService_serviceDesc.Streams[0], "/api_pb.CollabService/Edit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &collabServiceEditClient{stream}
+	return x, nil
+}
+
+type CollabService_EditClient interface {
+	Send(*Event) error
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type collabServiceEditClient struct {
+	grpc.ClientStream
+}
+
+func (x *collabServiceEditClient) Send(m *Event) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *collabServiceEditClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *collabServiceClient) ListDocuments(ctx context.Context, in *ListDocumentsRequest, opts ...grpc.CallOption) (*ListDocumentsResponse, error) {
+	out := new(ListDocumentsResponse)
+	err := c.cc.Invoke(ctx, "/api_pb.CollabService/ListDocuments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collabServiceClient) CreateDocument(ctx context.Context, in *CreateDocumentRequest, opts ...grpc.CallOption) (*Document, error) {
+	out := new(Document)
+	err := c.cc.Invoke(ctx, "/api_pb.CollabService/CreateDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *collabServiceClient) DeleteDocument(ctx context.Context, in *DeleteDocumentRequest, opts ...grpc.CallOption) (*DeleteDocumentResponse, error) {
+	out := new(DeleteDocumentResponse)
+	err := c.cc.Invoke(ctx, "/api_pb.CollabService/DeleteDocument", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CollabServiceServer is the server API for CollabService service.
+type CollabServiceServer interface {
+	// Edit joins a document and works like the websocket endpoint with the
+	// cloudocs.proto.v1 subprotocol: the server starts with Init or Resume,
+	// and the payloads of Events are encoded as protobuf. The document and
+	// user are given in the document-id and x-cloudocs-id metadata, and name,
+	// unit and version metadata work like the websocket query parameters.
+	Edit(CollabService_EditServer) error
+	ListDocuments(context.Context, *ListDocumentsRequest) (*ListDocumentsResponse, error)
+	CreateDocument(context.Context, *CreateDocumentRequest) (*Document, error)
+	// DeleteDocument fails with NOT_FOUND if the document does not exist.
+	// Clients editing the document get a DOCUMENT_DELETED error and are
+	// disconnected.
+	DeleteDocument(context.Context, *DeleteDocumentRequest) (*DeleteDocumentResponse, error)
+}
+
+// UnimplementedCollabServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedCollabServiceServer struct {
+}
+
+func (*UnimplementedCollabServiceServer) Edit(srv CollabService_EditServer) error {
+	return status.Errorf(codes.Unimplemented, "method Edit not implemented")
+}
+func (*UnimplementedCollabServiceServer) ListDocuments(ctx context.Context, req *ListDocumentsRequest) (*ListDocumentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDocuments not implemented")
+}
+func (*UnimplementedCollabServiceServer) CreateDocument(ctx context.Context, req *CreateDocumentRequest) (*Document, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDocument not implemented")
+}
+func (*UnimplementedCollabServiceServer) DeleteDocument(ctx context.Context, req *DeleteDocumentRequest) (*DeleteDocumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDocument not implemented")
+}
+
+func RegisterCollabServiceServer(s *grpc.Server, srv CollabServiceServer) {
+	s.RegisterService(&_CollabService_serviceDesc, srv)
+}
+
+func _CollabService_Edit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CollabServiceServer).Edit(&collabServiceEditServer{stream})
+}
+
+type CollabService_EditServer interface {
+	Send(*Event) error
+	Recv() (*Event, error)
+	grpc.ServerStream
+}
+
+type collabServiceEditServer struct {
+	grpc.ServerStream
+}
+
+func (x *collabServiceEditServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *collabServiceEditServer) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _CollabService_ListDocuments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDocumentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollabServiceServer).ListDocuments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api_pb.CollabService/ListDocuments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollabServiceServer).ListDocuments(ctx, req.(*ListDocumentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollabService_CreateDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollabServiceServer).CreateDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api_pb.CollabService/CreateDocument",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollabServiceServer).CreateDocument(ctx, req.(*CreateDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CollabService_DeleteDocument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CollabServiceServer).DeleteDocument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api_pb.CollabService/DeleteDocument",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CollabServiceServer).DeleteDocument(ctx, req.(*DeleteDocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CollabService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "api_pb.CollabService",
+	HandlerType: (*CollabServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDocuments",
+			Handler:    _CollabService_ListDocuments_Handler,
+		},
+		{
+			MethodName: "CreateDocument",
+			Handler:    _CollabService_CreateDocument_Handler,
+		},
+		{
+			MethodName: "DeleteDocument",
+			Handler:    _CollabService_DeleteDocument_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Edit",
+			Handler:       _CollabService_Edit_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
+
+func (m *Event) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Event) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Event) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Event) > 0 {
+		i -= len(m.Event)
+		copy(dAtA[i:], m.Event)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Event)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Type != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Envelope) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Envelope) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Event != nil {
+		{
+			size := m.Event.Size()
+			i -= size
+			if _, err := m.Event.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if len(m.RequestId) > 0 {
+		i -= len(m.RequestId)
+		copy(dAtA[i:], m.RequestId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.RequestId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Envelope_Init) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Init) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Init != nil {
+		{
+			size, err := m.Init.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_ClientJoined) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_ClientJoined) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.ClientJoined != nil {
+		{
+			size, err := m.ClientJoined.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_ClientQuit) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_ClientQuit) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.ClientQuit != nil {
+		{
+			size, err := m.ClientQuit.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x22
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Operation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Operation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Operation != nil {
+		{
+			size, err := m.Operation.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_OperationAck) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_OperationAck) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.OperationAck != nil {
+		{
+			size, err := m.OperationAck.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Cursor) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Cursor) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Cursor != nil {
+		{
+			size, err := m.Cursor.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Resume) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Resume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Resume != nil {
+		{
+			size, err := m.Resume.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Error) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Error) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Error != nil {
+		{
+			size, err := m.Error.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Checksum) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Checksum) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Checksum != nil {
+		{
+			size, err := m.Checksum.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x52
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Resync) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Resync) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Resync != nil {
+		{
+			size, err := m.Resync.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x5a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_OperationBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_OperationBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.OperationBatch != nil {
+		{
+			size, err := m.OperationBatch.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Hello) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Hello) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Hello != nil {
+		{
+			size, err := m.Hello.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x6a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_CrdtSync) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_CrdtSync) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CrdtSync != nil {
+		{
+			size, err := m.CrdtSync.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x72
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_CrdtUpdate) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_CrdtUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.CrdtUpdate != nil {
+		{
+			size, err := m.CrdtUpdate.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x7a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Undo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Undo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Undo != nil {
+		{
+			size, err := m.Undo.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Envelope_Redo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Envelope_Redo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Redo != nil {
+		{
+			size, err := m.Redo.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *Hello) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Hello) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Hello) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Capabilities) > 0 {
+		dAtA18 := make([]byte, len(m.Capabilities)*10)
+		var j17 int
+		for _, num := range m.Capabilities {
+			for num >= 1<<7 {
+				dAtA18[j17] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j17++
+			}
+			dAtA18[j17] = uint8(num)
+			j17++
+		}
+		i -= j17
+		copy(dAtA[i:], dAtA18[:j17])
+		i = encodeVarintApi(dAtA, i, uint64(j17))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.PositionUnit != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.PositionUnit))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.ProtocolVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.ProtocolVersion))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Participant) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Participant) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Participant) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.ConnectionId) > 0 {
+		i -= len(m.ConnectionId)
+		copy(dAtA[i:], m.ConnectionId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.ConnectionId)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Color) > 0 {
+		i -= len(m.Color)
+		copy(dAtA[i:], m.Color)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Color)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.UserId) > 0 {
+		i -= len(m.UserId)
+		copy(dAtA[i:], m.UserId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.UserId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Cursor) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Cursor) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Cursor) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.ConnectionId) > 0 {
+		i -= len(m.ConnectionId)
+		copy(dAtA[i:], m.ConnectionId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.ConnectionId)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Version != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Head != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Head))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Anchor != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Anchor))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.UserId) > 0 {
+		i -= len(m.UserId)
+		copy(dAtA[i:], m.UserId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.UserId)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Init) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Init) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Init) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Mode != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.Capabilities) > 0 {
+		dAtA20 := make([]byte, len(m.Capabilities)*10)
+		var j19 int
+		for _, num := range m.Capabilities {
+			for num >= 1<<7 {
+				dAtA20[j19] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j19++
+			}
+			dAtA20[j19] = uint8(num)
+			j19++
+		}
+		i -= j19
+		copy(dAtA[i:], dAtA20[:j19])
+		i = encodeVarintApi(dAtA, i, uint64(j19))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.ProtocolVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.ProtocolVersion))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.ConnectionId) > 0 {
+		i -= len(m.ConnectionId)
+		copy(dAtA[i:], m.ConnectionId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.ConnectionId)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.Cursors) > 0 {
+		for iNdEx := len(m.Cursors) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Cursors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if len(m.Participants) > 0 {
+		for iNdEx := len(m.Participants) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Participants[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.PositionUnit != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.PositionUnit))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.LastVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.LastVersion))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.DocumentName) > 0 {
+		i -= len(m.DocumentName)
+		copy(dAtA[i:], m.DocumentName)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.DocumentName)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Resume) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Resume) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Resume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Capabilities) > 0 {
+		dAtA22 := make([]byte, len(m.Capabilities)*10)
+		var j21 int
+		for _, num := range m.Capabilities {
+			for num >= 1<<7 {
+				dAtA22[j21] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j21++
+			}
+			dAtA22[j21] = uint8(num)
+			j21++
+		}
+		i -= j21
+		copy(dAtA[i:], dAtA22[:j21])
+		i = encodeVarintApi(dAtA, i, uint64(j21))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.ProtocolVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.ProtocolVersion))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.ConnectionId) > 0 {
+		i -= len(m.ConnectionId)
+		copy(dAtA[i:], m.ConnectionId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.ConnectionId)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.Cursors) > 0 {
+		for iNdEx := len(m.Cursors) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Cursors[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Participants) > 0 {
+		for iNdEx := len(m.Participants) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Participants[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Operations) > 0 {
+		for iNdEx := len(m.Operations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Operations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.LastVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.LastVersion))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Operation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Operation) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Operation) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.ConnectionId) > 0 {
+		i -= len(m.ConnectionId)
+		copy(dAtA[i:], m.ConnectionId)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.ConnectionId)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.Version != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Len != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Len))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Index != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Index))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Type != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Type))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.UserID) > 0 {
+		i -= len(m.UserID)
+		copy(dAtA[i:], m.UserID)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.UserID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OperationBatch) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OperationBatch) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OperationBatch) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Version != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Operations) > 0 {
+		for iNdEx := len(m.Operations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Operations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Undo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Undo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Undo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Redo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Redo) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Redo) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *OperationAck) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OperationAck) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *OperationAck) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Operations) > 0 {
+		for iNdEx := len(m.Operations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Operations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.LastVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.LastVersion))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Checksum) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Checksum) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Checksum) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Version != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Resync) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Resync) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Resync) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.LastVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.LastVersion))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Error) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Error) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Error) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Resync {
+		i--
+		if m.Resync {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Operation != nil {
+		{
+			size, err := m.Operation.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Code != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Code))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SyncRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SyncRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SyncRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.PositionUnit != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.PositionUnit))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Operations) > 0 {
+		for iNdEx := len(m.Operations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Operations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Version != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Version))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SyncResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SyncResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SyncResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Checksum != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Checksum))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Missed) > 0 {
+		for iNdEx := len(m.Missed) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Missed[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.Operations) > 0 {
+		for iNdEx := len(m.Operations) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Operations[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.LastVersion != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.LastVersion))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CrdtId) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CrdtId) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtId) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Clock != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Clock))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Site) > 0 {
+		i -= len(m.Site)
+		copy(dAtA[i:], m.Site)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Site)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CrdtInsert) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CrdtInsert) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtInsert) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Origin != nil {
+		{
+			size, err := m.Origin.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CrdtDelete) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CrdtDelete) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtDelete) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Len != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Len))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Target != nil {
+		{
+			size, err := m.Target.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CrdtOp) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CrdtOp) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtOp) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Op != nil {
+		{
+			size := m.Op.Size()
+			i -= size
+			if _, err := m.Op.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if m.Id != nil {
+		{
+			size, err := m.Id.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CrdtOp_Insert) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtOp_Insert) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Insert != nil {
+		{
+			size, err := m.Insert.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+func (m *CrdtOp_Delete) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtOp_Delete) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	if m.Delete != nil {
+		{
+			size, err := m.Delete.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintApi(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	return len(dAtA) - i, nil
+}
+func (m *CrdtUpdate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CrdtUpdate) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Ops) > 0 {
+		for iNdEx := len(m.Ops) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Ops[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CrdtSync) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CrdtSync) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CrdtSync) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Ops) > 0 {
+		for iNdEx := len(m.Ops) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Ops[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.StateVector) > 0 {
+		for k := range m.StateVector {
+			v := m.StateVector[k]
+			baseI := i
+			i = encodeVarintApi(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintApi(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintApi(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Document) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Document) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Document) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Mode != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Author) > 0 {
+		i -= len(m.Author)
+		copy(dAtA[i:], m.Author)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Author)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ListDocumentsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListDocumentsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ListDocumentsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ListDocumentsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ListDocumentsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ListDocumentsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Documents) > 0 {
+		for iNdEx := len(m.Documents) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Documents[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintApi(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *CreateDocumentRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateDocumentRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *CreateDocumentRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Mode != 0 {
+		i = encodeVarintApi(dAtA, i, uint64(m.Mode))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Author) > 0 {
+		i -= len(m.Author)
+		copy(dAtA[i:], m.Author)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Author)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteDocumentRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteDocumentRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteDocumentRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintApi(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *DeleteDocumentResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DeleteDocumentResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DeleteDocumentResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintApi(dAtA []byte, offset int, v uint64) int {
+	offset -= sovApi(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Event) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Type != 0 {
+		n += 1 + sovApi(uint64(m.Type))
+	}
+	l = len(m.Event)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Envelope) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.RequestId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Event != nil {
+		n += m.Event.Size()
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Envelope_Init) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Init != nil {
+		l = m.Init.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_ClientJoined) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ClientJoined != nil {
+		l = m.ClientJoined.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_ClientQuit) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ClientQuit != nil {
+		l = m.ClientQuit.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Operation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Operation != nil {
+		l = m.Operation.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_OperationAck) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.OperationAck != nil {
+		l = m.OperationAck.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Cursor) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Cursor != nil {
+		l = m.Cursor.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Resume) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Resume != nil {
+		l = m.Resume.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Error) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Error != nil {
+		l = m.Error.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Checksum) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Checksum != nil {
+		l = m.Checksum.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Resync) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Resync != nil {
+		l = m.Resync.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_OperationBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.OperationBatch != nil {
+		l = m.OperationBatch.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Hello) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Hello != nil {
+		l = m.Hello.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_CrdtSync) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CrdtSync != nil {
+		l = m.CrdtSync.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_CrdtUpdate) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.CrdtUpdate != nil {
+		l = m.CrdtUpdate.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Undo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Undo != nil {
+		l = m.Undo.Size()
+		n += 2 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Envelope_Redo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Redo != nil {
+		l = m.Redo.Size()
+		n += 2 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *Hello) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovApi(uint64(m.ProtocolVersion))
+	}
+	if m.PositionUnit != 0 {
+		n += 1 + sovApi(uint64(m.PositionUnit))
+	}
+	if len(m.Capabilities) > 0 {
+		l = 0
+		for _, e := range m.Capabilities {
+			l += sovApi(uint64(e))
+		}
+		n += 1 + sovApi(uint64(l)) + l
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Participant) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.UserId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Color)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.ConnectionId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Cursor) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.UserId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Anchor != 0 {
+		n += 1 + sovApi(uint64(m.Anchor))
+	}
+	if m.Head != 0 {
+		n += 1 + sovApi(uint64(m.Head))
+	}
+	if m.Version != 0 {
+		n += 1 + sovApi(uint64(m.Version))
+	}
+	l = len(m.ConnectionId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Init) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.DocumentName)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Text)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.LastVersion != 0 {
+		n += 1 + sovApi(uint64(m.LastVersion))
+	}
+	if m.PositionUnit != 0 {
+		n += 1 + sovApi(uint64(m.PositionUnit))
+	}
+	if len(m.Participants) > 0 {
+		for _, e := range m.Participants {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if len(m.Cursors) > 0 {
+		for _, e := range m.Cursors {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	l = len(m.ConnectionId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovApi(uint64(m.ProtocolVersion))
+	}
+	if len(m.Capabilities) > 0 {
+		l = 0
+		for _, e := range m.Capabilities {
+			l += sovApi(uint64(e))
+		}
+		n += 1 + sovApi(uint64(l)) + l
+	}
+	if m.Mode != 0 {
+		n += 1 + sovApi(uint64(m.Mode))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Resume) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LastVersion != 0 {
+		n += 1 + sovApi(uint64(m.LastVersion))
+	}
+	if len(m.Operations) > 0 {
+		for _, e := range m.Operations {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if len(m.Participants) > 0 {
+		for _, e := range m.Participants {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if len(m.Cursors) > 0 {
+		for _, e := range m.Cursors {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	l = len(m.ConnectionId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.ProtocolVersion != 0 {
+		n += 1 + sovApi(uint64(m.ProtocolVersion))
+	}
+	if len(m.Capabilities) > 0 {
+		l = 0
+		for _, e := range m.Capabilities {
+			l += sovApi(uint64(e))
+		}
+		n += 1 + sovApi(uint64(l)) + l
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Operation) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.UserID)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Type != 0 {
+		n += 1 + sovApi(uint64(m.Type))
+	}
+	if m.Index != 0 {
+		n += 1 + sovApi(uint64(m.Index))
+	}
+	if m.Len != 0 {
+		n += 1 + sovApi(uint64(m.Len))
+	}
+	l = len(m.Text)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovApi(uint64(m.Version))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovApi(uint64(m.Checksum))
+	}
+	l = len(m.ConnectionId)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *OperationBatch) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Operations) > 0 {
+		for _, e := range m.Operations {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if m.Version != 0 {
+		n += 1 + sovApi(uint64(m.Version))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovApi(uint64(m.Checksum))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Undo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Redo) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *OperationAck) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LastVersion != 0 {
+		n += 1 + sovApi(uint64(m.LastVersion))
+	}
+	if len(m.Operations) > 0 {
+		for _, e := range m.Operations {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovApi(uint64(m.Checksum))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Checksum) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Version != 0 {
+		n += 1 + sovApi(uint64(m.Version))
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovApi(uint64(m.Checksum))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Resync) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Text)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.LastVersion != 0 {
+		n += 1 + sovApi(uint64(m.LastVersion))
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovApi(uint64(m.Checksum))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Error) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Code != 0 {
+		n += 1 + sovApi(uint64(m.Code))
+	}
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Operation != nil {
+		l = m.Operation.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Resync {
+		n += 2
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SyncRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Version != 0 {
+		n += 1 + sovApi(uint64(m.Version))
+	}
+	if len(m.Operations) > 0 {
+		for _, e := range m.Operations {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.PositionUnit != 0 {
+		n += 1 + sovApi(uint64(m.PositionUnit))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *SyncResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.LastVersion != 0 {
+		n += 1 + sovApi(uint64(m.LastVersion))
+	}
+	if len(m.Operations) > 0 {
+		for _, e := range m.Operations {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if len(m.Missed) > 0 {
+		for _, e := range m.Missed {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if m.Checksum != 0 {
+		n += 1 + sovApi(uint64(m.Checksum))
+	}
+	l = len(m.Text)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CrdtId) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Site)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Clock != 0 {
+		n += 1 + sovApi(uint64(m.Clock))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CrdtInsert) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Origin != nil {
+		l = m.Origin.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Text)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CrdtDelete) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Target != nil {
+		l = m.Target.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Len != 0 {
+		n += 1 + sovApi(uint64(m.Len))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CrdtOp) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Id != nil {
+		l = m.Id.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Op != nil {
+		n += m.Op.Size()
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CrdtOp_Insert) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Insert != nil {
+		l = m.Insert.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *CrdtOp_Delete) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Delete != nil {
+		l = m.Delete.Size()
+		n += 1 + l + sovApi(uint64(l))
+	}
+	return n
+}
+func (m *CrdtUpdate) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Ops) > 0 {
+		for _, e := range m.Ops {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CrdtSync) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.StateVector) > 0 {
+		for k, v := range m.StateVector {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovApi(uint64(len(k))) + 1 + sovApi(uint64(v))
+			n += mapEntrySize + 1 + sovApi(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Ops) > 0 {
+		for _, e := range m.Ops {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Document) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Author)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovApi(uint64(m.Mode))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ListDocumentsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ListDocumentsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Documents) > 0 {
+		for _, e := range m.Documents {
+			l = e.Size()
+			n += 1 + l + sovApi(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *CreateDocumentRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	l = len(m.Author)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.Mode != 0 {
+		n += 1 + sovApi(uint64(m.Mode))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DeleteDocumentRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovApi(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *DeleteDocumentResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func sovApi(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozApi(x uint64) (n int) {
+	return sovApi(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Event) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Event: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Event: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= Event_EventType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Event", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Event = append(m.Event[:0], dAtA[iNdEx:postIndex]...)
+			if m.Event == nil {
+				m.Event = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Envelope) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Envelope: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Envelope: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RequestId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Init", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Init{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Init{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientJoined", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Participant{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_ClientJoined{v}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClientQuit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Participant{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_ClientQuit{v}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operation", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Operation{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Operation{v}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationAck", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &OperationAck{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_OperationAck{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursor", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Cursor{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Cursor{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resume", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Resume{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Resume{v}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Error{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Error{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Checksum{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Checksum{v}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resync", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Resync{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Resync{v}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OperationBatch", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &OperationBatch{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_OperationBatch{v}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hello", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Hello{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Hello{v}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CrdtSync", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CrdtSync{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_CrdtSync{v}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CrdtUpdate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CrdtUpdate{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_CrdtUpdate{v}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Undo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Undo{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Undo{v}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Redo", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &Redo{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Event = &Envelope_Redo{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Hello) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Hello: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Hello: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PositionUnit", wireType)
+			}
+			m.PositionUnit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PositionUnit |= PositionUnit(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType == 0 {
+				var v Capability
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= Capability(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Capabilities = append(m.Capabilities, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthApi
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthApi
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.Capabilities) == 0 {
+					m.Capabilities = make([]Capability, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v Capability
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= Capability(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Capabilities = append(m.Capabilities, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Participant) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Participant: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Participant: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Color", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Color = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConnectionId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConnectionId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Cursor) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Cursor: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Cursor: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Anchor", wireType)
+			}
+			m.Anchor = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Anchor |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Head", wireType)
+			}
+			m.Head = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Head |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConnectionId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConnectionId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Init) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Init: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Init: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DocumentName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DocumentName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastVersion", wireType)
+			}
+			m.LastVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PositionUnit", wireType)
+			}
+			m.PositionUnit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PositionUnit |= PositionUnit(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Participants", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Participants = append(m.Participants, &Participant{})
+			if err := m.Participants[len(m.Participants)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cursors = append(m.Cursors, &Cursor{})
+			if err := m.Cursors[len(m.Cursors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConnectionId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConnectionId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType == 0 {
+				var v Capability
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= Capability(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Capabilities = append(m.Capabilities, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthApi
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthApi
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.Capabilities) == 0 {
+					m.Capabilities = make([]Capability, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v Capability
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= Capability(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Capabilities = append(m.Capabilities, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= DocumentMode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Resume) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Resume: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Resume: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastVersion", wireType)
+			}
+			m.LastVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operations = append(m.Operations, &Operation{})
+			if err := m.Operations[len(m.Operations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Participants", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Participants = append(m.Participants, &Participant{})
+			if err := m.Participants[len(m.Participants)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursors", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cursors = append(m.Cursors, &Cursor{})
+			if err := m.Cursors[len(m.Cursors)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConnectionId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConnectionId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProtocolVersion", wireType)
+			}
+			m.ProtocolVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProtocolVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType == 0 {
+				var v Capability
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= Capability(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Capabilities = append(m.Capabilities, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthApi
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthApi
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.Capabilities) == 0 {
+					m.Capabilities = make([]Capability, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v Capability
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= Capability(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Capabilities = append(m.Capabilities, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Operation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Operation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Operation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			m.Type = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Type |= OpType(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Len", wireType)
+			}
+			m.Len = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Len |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ConnectionId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ConnectionId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OperationBatch) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OperationBatch: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OperationBatch: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operations = append(m.Operations, &Operation{})
+			if err := m.Operations[len(m.Operations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Undo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Undo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Undo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Redo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Redo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Redo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *OperationAck) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: OperationAck: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: OperationAck: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastVersion", wireType)
+			}
+			m.LastVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operations = append(m.Operations, &Operation{})
+			if err := m.Operations[len(m.Operations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Checksum) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Checksum: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Checksum: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Resync) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Resync: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Resync: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastVersion", wireType)
+			}
+			m.LastVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Error) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Error: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Error: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
+			}
+			m.Code = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Code |= ErrorCode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operation", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Operation == nil {
+				m.Operation = &Operation{}
+			}
+			if err := m.Operation.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Resync", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Resync = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SyncRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SyncRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SyncRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operations = append(m.Operations, &Operation{})
+			if err := m.Operations[len(m.Operations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PositionUnit", wireType)
+			}
+			m.PositionUnit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PositionUnit |= PositionUnit(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SyncResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SyncResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SyncResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastVersion", wireType)
+			}
+			m.LastVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.LastVersion |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Operations", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Operations = append(m.Operations, &Operation{})
+			if err := m.Operations[len(m.Operations)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Missed", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Missed = append(m.Missed, &Operation{})
+			if err := m.Missed[len(m.Missed)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checksum", wireType)
+			}
+			m.Checksum = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Checksum |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CrdtId) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CrdtId: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CrdtId: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Site", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Site = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Clock", wireType)
+			}
+			m.Clock = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Clock |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CrdtInsert) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CrdtInsert: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CrdtInsert: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Origin == nil {
+				m.Origin = &CrdtId{}
+			}
+			if err := m.Origin.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Text = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CrdtDelete) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CrdtDelete: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CrdtDelete: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Target == nil {
+				m.Target = &CrdtId{}
+			}
+			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Len", wireType)
+			}
+			m.Len = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Len |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CrdtOp) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CrdtOp: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CrdtOp: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Id == nil {
+				m.Id = &CrdtId{}
+			}
+			if err := m.Id.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Insert", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CrdtInsert{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &CrdtOp_Insert{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Delete", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &CrdtDelete{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Op = &CrdtOp_Delete{v}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CrdtUpdate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CrdtUpdate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CrdtUpdate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ops", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ops = append(m.Ops, &CrdtOp{})
+			if err := m.Ops[len(m.Ops)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CrdtSync) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CrdtSync: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CrdtSync: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StateVector", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.StateVector == nil {
+				m.StateVector = make(map[string]uint64)
+			}
+			var mapkey string
+			var mapvalue uint64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowApi
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthApi
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowApi
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipApi(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthApi
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.StateVector[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ops", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ops = append(m.Ops, &CrdtOp{})
+			if err := m.Ops[len(m.Ops)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Document) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Document: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Document: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthApi
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthApi
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Author = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
+			}
+			m.Mode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApi
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Mode |= DocumentMode(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
 	}
-	return n
-}
 
-func (m *OperationAck) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.LastVersion != 0 {
-		n += 1 + sovApi(uint64(m.LastVersion))
-	}
-	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
+	return nil
 }
+func (m *ListDocumentsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowApi
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ListDocumentsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ListDocumentsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipApi(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthApi
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
 
-func sovApi(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozApi(x uint64) (n int) {
-	return sovApi(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *Event) Unmarshal(dAtA []byte) error {
+func (m *ListDocumentsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -681,36 +9962,17 @@ func (m *Event) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Event: wiretype end group for non-group")
+			return fmt.Errorf("proto: ListDocumentsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Event: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ListDocumentsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
-			}
-			m.Type = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Type |= Event_EventType(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Event", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Documents", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -720,24 +9982,24 @@ func (m *Event) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthApi
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthApi
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Event = append(m.Event[:0], dAtA[iNdEx:postIndex]...)
-			if m.Event == nil {
-				m.Event = []byte{}
+			m.Documents = append(m.Documents, &Document{})
+			if err := m.Documents[len(m.Documents)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
 		default:
@@ -762,7 +10024,7 @@ func (m *Event) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Init) Unmarshal(dAtA []byte) error {
+func (m *CreateDocumentRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -785,15 +10047,15 @@ func (m *Init) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Init: wiretype end group for non-group")
+			return fmt.Errorf("proto: CreateDocumentRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Init: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: CreateDocumentRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DocumentName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -821,11 +10083,11 @@ func (m *Init) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.DocumentName = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Author", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -853,13 +10115,13 @@ func (m *Init) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Text = string(dAtA[iNdEx:postIndex])
+			m.Author = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastVersion", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Mode", wireType)
 			}
-			m.LastVersion = 0
+			m.Mode = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowApi
@@ -869,7 +10131,7 @@ func (m *Init) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.LastVersion |= int32(b&0x7F) << shift
+				m.Mode |= DocumentMode(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -896,7 +10158,7 @@ func (m *Init) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Operation) Unmarshal(dAtA []byte) error {
+func (m *DeleteDocumentRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -919,104 +10181,15 @@ func (m *Operation) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Operation: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteDocumentRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Operation: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteDocumentRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UserID", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthApi
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthApi
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.UserID = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
-			}
-			m.Type = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Type |= OpType(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
-			}
-			m.Index = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Index |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Len", wireType)
-			}
-			m.Len = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Len |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Text", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1044,27 +10217,8 @@ func (m *Operation) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Text = string(dAtA[iNdEx:postIndex])
+			m.Id = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
-			}
-			m.Version = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Version |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -1087,7 +10241,7 @@ func (m *Operation) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *OperationAck) Unmarshal(dAtA []byte) error {
+func (m *DeleteDocumentResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1110,31 +10264,12 @@ func (m *OperationAck) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: OperationAck: wiretype end group for non-group")
+			return fmt.Errorf("proto: DeleteDocumentResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: OperationAck: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: DeleteDocumentResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
-		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field LastVersion", wireType)
-			}
-			m.LastVersion = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowApi
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.LastVersion |= int32(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApi(dAtA[iNdEx:])
@@ -8,8 +8,14 @@ type AuthRequest struct {
 type CreateDocRequest struct {
 	Name   string `json:"name"`
 	Author string `json:"author"`
+	// Mode is "ot" or "crdt", documents use OT by default
+	Mode string `json:"mode"`
 }
 
 type SaveDocumentRequest struct {
 	Text string `json:"text"`
 }
+
+type RestoreRequest struct {
+	Version int32 `json:"version"`
+}
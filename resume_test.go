@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"testing"
+)
+
+func TestResume(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := dialTestClient(t, srv, doc.ID, "user")
+	defer c.conn.Close()
+	c.insert(0, "X")
+	c.insert(1, "Y")
+
+	conn := dial(t, srv, doc.ID+"?version=1", "other")
+	defer conn.Close()
+	ev := readEvent(t, conn)
+	if ev.Type != api_pb.Event_RESUME {
+		t.Fatalf("got %v, want RESUME", ev.Type)
+	}
+	var resume api_pb.Resume
+	decoder.Unmarshal(bytes.NewReader(ev.Event), &resume)
+	if resume.LastVersion != 2 || len(resume.Operations) != 1 ||
+		resume.Operations[0].Text != "Y" || resume.Operations[0].Version != 2 {
+		t.Errorf("got %v", &resume)
+	}
+}
+
+func TestResumeCompacted(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := dialTestClient(t, srv, doc.ID, "user")
+	defer c.conn.Close()
+	c.insert(0, "X")
+	c.insert(1, "Y")
+	compact(t, doc)
+
+	// the operations after version 1 are gone, so the client starts over
+	conn := dial(t, srv, doc.ID+"?version=1", "other")
+	defer conn.Close()
+	ev := readEvent(t, conn)
+	if ev.Type != api_pb.Event_INIT {
+		t.Fatalf("got %v, want INIT", ev.Type)
+	}
+	var init api_pb.Init
+	decoder.Unmarshal(bytes.NewReader(ev.Event), &init)
+	if init.LastVersion != 2 || init.Text != "XYabc" {
+		t.Errorf("got version %v and %q", init.LastVersion, init.Text)
+	}
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"github.com/ssau-fiit/cloudocs-api/common/uuid"
+	"github.com/ssau-fiit/cloudocs-api/crdt"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"time"
+)
+
+// session owns everything about an open document: its text, history and the set
+// of connected clients. All of it is only touched from the run goroutine, other
+// goroutines talk to the session through its channels.
+//
+// The text held by the sequencer of the document is the authoritative one.
+// Revisions are written to the operation log as they are applied, while
+// texts.<id> is only refreshed every flushInterval and when the session stops.
+type session struct {
+	doc Document
+	// token owns the lease while the session is the sequencer and tells
+	// apart the messages it publishes
+	token string
+
+	join     chan *client
+	leave    chan *client
+	messages chan *message
+	calls    chan func()
+	// cluster gets the messages published on the channel of the document
+	cluster chan *redis.Message
+	done    chan struct{}
+	stopped chan struct{}
+
+	text    *ot.Buffer
+	dirty   bool
+	history *ot.History
+	// deleted is set once the document is gone, nothing gets written for it
+	// anymore
+	deleted bool
+	clients map[*client]struct{}
+	// crdt is the document of sessions in CRDT mode, text follows it
+	crdt *crdt.Doc
+
+	// leader is set while this replica is the sequencer of the document
+	leader bool
+	// pending holds the operations forwarded to the sequencer by their id
+	pending   map[ot.RevisionID]*pendingOp
+	forwarded int
+	// remote holds the participants connected to other replicas
+	remote map[string]*remotePresence
+	// undo holds what every user can undo and redo
+	undo map[string]*undoStack
+}
+
+// message is an event payload received from one of the clients. requestID is
+// the id the client gave the request, if any.
+type message struct {
+	client    *client
+	requestID string
+	msg       proto.Message
+}
+
+func newSession(doc Document) *session {
+	return &session{
+		doc:      doc,
+		token:    fmt.Sprintf("%v-%v", instanceID, uuid.Must(uuid.NewV4())),
+		join:     make(chan *client),
+		leave:    make(chan *client),
+		messages: make(chan *message),
+		calls:    make(chan func()),
+		cluster:  make(chan *redis.Message, 100),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		clients:  make(map[*client]struct{}),
+		pending:  make(map[ot.RevisionID]*pendingOp),
+		remote:   make(map[string]*remotePresence),
+		undo:     make(map[string]*undoStack),
+	}
+}
+
+func (s *session) run() {
+	defer close(s.stopped)
+
+	s.leader = s.lead()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	var text *ot.Buffer
+	var history *ot.History
+	var err error
+	if s.doc.Mode == modeCRDT {
+		var doc *crdt.Doc
+		if doc, err = loadCRDT(ctx, s.doc.ID); err == nil {
+			// CRDT documents have no revisions, the history stays empty
+			s.crdt = doc
+			text, history = ot.NewBuffer(doc.String()), ot.NewHistory(0)
+		}
+	} else {
+		text, history, err = loadDocument(ctx, s.doc.ID)
+	}
+	if err == nil && s.leader {
+		err = saveText(ctx, s.doc.ID, text.String())
+	}
+	cancel()
+	if err != nil {
+		log.Error().Err(err).Str("document", s.doc.ID).Msg("error loading document")
+	} else {
+		s.text = text
+		s.history = history
+		s.publishPresence(true)
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	leaseTicker := time.NewTicker(leaseTTL / 3)
+	defer leaseTicker.Stop()
+
+	for {
+		select {
+		case cl := <-s.join:
+			s.handleJoin(cl)
+		case cl := <-s.leave:
+			s.handleLeave(cl)
+		case m := <-s.messages:
+			if _, ok := s.clients[m.client]; !ok {
+				continue
+			}
+			if s.deleted {
+				m.client.fail(m.requestID, errDocumentDeleted, nil)
+				continue
+			}
+			if !s.supports(m.msg) {
+				m.client.fail(m.requestID, fmt.Errorf("%w: not available in %v mode", errUnsupportedEvent, s.doc.Mode), nil)
+				continue
+			}
+			switch msg := m.msg.(type) {
+			case *api_pb.Operation:
+				s.handleBatch(m.client, m.requestID, &api_pb.OperationBatch{
+					Operations: []*api_pb.Operation{msg},
+					Version:    msg.Version,
+					Id:         msg.Id,
+				})
+			case *api_pb.OperationBatch:
+				s.handleBatch(m.client, m.requestID, msg)
+			case *api_pb.Cursor:
+				s.handleCursor(m.client, m.requestID, msg)
+			case *api_pb.Checksum:
+				s.handleChecksum(m.client, msg)
+			case *api_pb.CrdtUpdate:
+				s.handleCrdtUpdate(m.client, m.requestID, msg)
+			case *api_pb.CrdtSync:
+				s.handleCrdtSync(m.client, m.requestID, msg)
+			case *api_pb.Undo:
+				s.handleUndo(m.client, m.requestID, false)
+			case *api_pb.Redo:
+				s.handleUndo(m.client, m.requestID, true)
+			}
+		case msg := <-s.cluster:
+			var m clusterMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				log.Error().Err(err).Msg("error decoding cluster message")
+				continue
+			}
+			s.handleCluster(&m)
+		case f := <-s.calls:
+			f()
+		case <-ticker.C:
+			s.flush()
+		case <-leaseTicker.C:
+			s.renew()
+		case <-s.done:
+			s.flush()
+			if s.dirty {
+				// the operation log still has every revision, so the text is
+				// rebuilt from it on the next load
+				dirtyDocuments.Add(-1)
+			}
+			s.stop()
+			return
+		}
+	}
+}
+
+// submit hands msg, sent by cl with the given request id, over to the session
+// goroutine, unless cl gets closed first.
+func (s *session) submit(cl *client, requestID string, msg proto.Message) {
+	select {
+	case s.messages <- &message{client: cl, requestID: requestID, msg: msg}:
+	case <-cl.quit:
+	}
+}
+
+// do runs f in the session goroutine and waits for it to return.
+func (s *session) do(f func()) {
+	done := make(chan struct{})
+	s.calls <- func() {
+		defer close(done)
+		f()
+	}
+	<-done
+}
+
+func (s *session) handleJoin(cl *client) {
+	if s.history == nil || s.deleted {
+		cl.close()
+		return
+	}
+	cl.participant = &api_pb.Participant{
+		UserId:       cl.id,
+		Name:         cl.name,
+		Color:        nextColor(s.participants()),
+		ConnectionId: cl.connID,
+	}
+	s.clients[cl] = struct{}{}
+	defer s.notify(api_pb.Event_CLIENT_JOINED, cl.participant, cl)
+	defer s.publishPresence(false)
+
+	// CRDT clients catch up with a CrdtSync instead
+	if cl.resume != nil && s.crdt == nil && s.resume(cl) {
+		return
+	}
+
+	// sending initial message containing document info and text
+	cl.reply(cl.hello, api_pb.Event_INIT, &api_pb.Init{
+		DocumentName:    s.doc.Name,
+		Text:            s.text.String(),
+		LastVersion:     s.history.Version(),
+		PositionUnit:    cl.unit,
+		Participants:    s.participants(),
+		Cursors:         s.cursors(cl),
+		ConnectionId:    cl.connID,
+		ProtocolVersion: cl.version,
+		Capabilities:    cl.capabilities,
+		Mode:            s.mode(),
+	})
+}
+
+// mode returns the mode of the document as sent to clients.
+func (s *session) mode() api_pb.DocumentMode {
+	if s.crdt != nil {
+		return api_pb.DocumentMode_CRDT
+	}
+	return api_pb.DocumentMode_OT
+}
+
+// supports reports whether msg can be sent to a document in the mode of the
+// session. CRDT documents have no versions, so they take CRDT events only.
+func (s *session) supports(msg proto.Message) bool {
+	switch msg.(type) {
+	case *api_pb.CrdtUpdate, *api_pb.CrdtSync:
+		return s.crdt != nil
+	}
+	return s.crdt == nil
+}
+
+// resume sends a reconnecting client everything applied since the version it
+// had seen. It returns false if the history does not reach back that far.
+func (s *session) resume(cl *client) bool {
+	revs, err := s.history.Since(*cl.resume)
+	if err != nil {
+		return false
+	}
+
+	var ops []*api_pb.Operation
+	for _, rev := range revs {
+		ops = append(ops, rev.In(cl.unit)...)
+	}
+	cl.reply(cl.hello, api_pb.Event_RESUME, &api_pb.Resume{
+		LastVersion:     s.history.Version(),
+		Operations:      ops,
+		Participants:    s.participants(),
+		Cursors:         s.cursors(cl),
+		ConnectionId:    cl.connID,
+		ProtocolVersion: cl.version,
+		Capabilities:    cl.capabilities,
+	})
+	return true
+}
+
+func (s *session) handleLeave(cl *client) {
+	if _, ok := s.clients[cl]; !ok {
+		return
+	}
+	delete(s.clients, cl)
+	s.notify(api_pb.Event_CLIENT_QUIT, cl.participant, cl)
+	s.publishPresence(false)
+}
+
+// closeDeleted disconnects the clients of a document that was deleted, telling
+// them why. Operations still waiting for the sequencer fail.
+func (s *session) closeDeleted() {
+	if s.deleted {
+		return
+	}
+	s.deleted = true
+	if s.dirty {
+		s.dirty = false
+		dirtyDocuments.Add(-1)
+	}
+
+	for key, p := range s.pending {
+		delete(s.pending, key)
+		p.done(nil, errDocumentDeleted)
+	}
+	for cl := range s.clients {
+		cl.fail("", errDocumentDeleted, nil)
+		cl.end()
+	}
+}
+
+// handleBatch applies the operations of batch, which the client made one after
+// another, as a single revision. The ack or error answers requestID.
+func (s *session) handleBatch(cl *client, requestID string, batch *api_pb.OperationBatch) {
+	if rev := s.history.Find(cl.id, batch.Id); rev != nil {
+		cl.reply(requestID, api_pb.Event_OPERATION_ACK, &api_pb.OperationAck{
+			LastVersion: rev.Version,
+			Operations:  rev.In(cl.unit),
+			Checksum:    rev.Checksum,
+		})
+		return
+	}
+
+	if len(batch.Operations) == 0 {
+		cl.fail(requestID, fmt.Errorf("%w: batch without operations", ot.ErrInvalid), nil)
+		return
+	}
+
+	var op *api_pb.Operation
+	if len(batch.Operations) == 1 {
+		op = batch.Operations[0]
+	}
+	s.sequence(batch.Id, &pendingOp{
+		client:       cl,
+		userID:       cl.id,
+		connectionID: cl.connID,
+		ops:          batch.Operations,
+		version:      batch.Version,
+		unit:         cl.unit,
+		done: func(rev *ot.Revision, err error) {
+			if err != nil {
+				log.Error().Err(err).Msg("error while doing operation")
+				s.fail(cl, requestID, err, op)
+				return
+			}
+			log.Debug().Interface("operations", batch.Operations).Msg("operations received")
+
+			if rev == nil {
+				// the operations cancelled each other out
+				cl.reply(requestID, api_pb.Event_OPERATION_ACK, &api_pb.OperationAck{
+					LastVersion: s.history.Version(),
+					Checksum:    s.text.Checksum(),
+				})
+				return
+			}
+			cl.reply(requestID, api_pb.Event_OPERATION_ACK, &api_pb.OperationAck{
+				LastVersion: rev.Version,
+				Operations:  rev.In(cl.unit),
+				Checksum:    rev.Checksum,
+			})
+		},
+	})
+}
+
+// apply transforms the operations of p against everything applied since the
+// version they were based on, applies them to the document text and records
+// them as a new revision with the given id, which is persisted in the
+// operation log and published to the other replicas. Only the sequencer
+// applies operations. If they change nothing, no revision is made and apply
+// returns nil, unless they undo or redo a revision, which has to be recorded
+// to move the undo stacks.
+func (s *session) apply(id string, p *pendingOp) (*ot.Revision, error) {
+	// the revision may have been reverted since the request was made
+	if p.reverts != 0 && !s.revertible(p.userID, p.reverts) {
+		return nil, errNothingToUndo
+	}
+	ops, err := s.history.Transform(p.ops, p.version, p.unit)
+	if err != nil {
+		return nil, err
+	}
+	ops = ot.Compose(ops, p.unit)
+	if len(ops) == 0 && p.reverts == 0 {
+		return nil, nil
+	}
+	text := s.text.Clone()
+	change, err := text.Apply(ops, p.unit)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := s.history.Next(p.userID, p.connectionID, id, change, text.Checksum())
+	rev.Reverts = p.reverts
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := saveRevision(ctx, s.doc.ID, rev); err != nil {
+		return nil, err
+	}
+
+	if err := s.history.Append(rev); err != nil {
+		return nil, err
+	}
+	s.text = text
+	s.markDirty()
+	s.publish(&clusterMessage{Kind: kindRevision, Revision: rev})
+
+	if snapshotInterval > 0 && rev.Version%snapshotInterval == 0 {
+		if err := saveSnapshot(ctx, s.doc.ID, text.String(), s.history); err != nil {
+			log.Error().Err(err).Str("document", s.doc.ID).Msg("error saving snapshot")
+		}
+	}
+	return rev, nil
+}
+
+// broadcast sends the operations of rev to every client except the one that
+// made them, if any. A revision of several operations goes out as one batch so
+// clients apply it as a whole, unless the client doesn't support batches.
+func (s *session) broadcast(rev *ot.Revision, except *client) {
+	for cl := range s.clients {
+		if cl != except {
+			cl.revision("", rev)
+		}
+	}
+}
+
+// revision sends the operations of rev to the client, answering the request
+// with the given id, if any.
+func (cl *client) revision(requestID string, rev *ot.Revision) {
+	ops := rev.In(cl.unit)
+	if len(ops) == 1 || !cl.can(api_pb.Capability_BATCHING) {
+		for _, op := range ops {
+			cl.reply(requestID, api_pb.Event_OPERATION, op)
+		}
+		return
+	}
+	cl.reply(requestID, api_pb.Event_OPERATION_BATCH, &api_pb.OperationBatch{
+		Operations: ops,
+		Version:    rev.Version,
+		Id:         rev.ID,
+		Checksum:   rev.Checksum,
+	})
+}
+
+// notify sends an event to every client except one.
+func (s *session) notify(t api_pb.Event_EventType, msg proto.Message, except *client) {
+	for cl := range s.clients {
+		if cl != except {
+			cl.send(t, msg)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"strings"
+	"testing"
+)
+
+func TestEmptyBatches(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := &testClient{t: t, conn: dial(t, srv, doc.ID, "user")}
+	defer c.conn.Close()
+	readEvent(t, c.conn)
+
+	c.write(api_pb.Event_OPERATION_BATCH, &api_pb.OperationBatch{Id: "empty"})
+	ev := readEvent(t, c.conn)
+	var e api_pb.Error
+	decoder.Unmarshal(bytes.NewReader(ev.Event), &e)
+	if ev.Type != api_pb.Event_ERROR || e.Code != api_pb.ErrorCode_INVALID_OPERATION {
+		t.Fatalf("got %v %v for a batch without operations", ev.Type, e.Code)
+	}
+
+	// operations cancelling each other out are acked without a revision
+	for i, want := range []int32{0, 1} {
+		ops := []*api_pb.Operation{{Type: api_pb.OpType_INSERT, Index: 0, Text: "x", Len: 1}}
+		if i == 0 {
+			ops = append(ops, &api_pb.Operation{Type: api_pb.OpType_DELETE, Index: 0, Len: 1})
+		}
+		c.write(api_pb.Event_OPERATION_BATCH, &api_pb.OperationBatch{Operations: ops, Id: fmt.Sprint(i)})
+		ev := readEvent(t, c.conn)
+		var ack api_pb.OperationAck
+		decoder.Unmarshal(bytes.NewReader(ev.Event), &ack)
+		if ev.Type != api_pb.Event_OPERATION_ACK || ack.LastVersion != want {
+			t.Fatalf("batch %v: got %v at version %v, want an ack at version %v", i, ev.Type, ack.LastVersion, want)
+		}
+		if ack.Checksum != ot.Checksum([]string{"abc", "xabc"}[i]) {
+			t.Errorf("batch %v: got checksum %v", i, ack.Checksum)
+		}
+	}
+}
+
+func BenchmarkSessionApply(b *testing.B) {
+	doc := newTestDocument(b, strings.Repeat("lorem ipsum ", 400_000))
+	s := sessions.acquire(doc)
+	defer sessions.release(s)
+
+	var err error
+	b.ResetTimer()
+	s.do(func() {
+		for i := 0; i < b.N && err == nil; i++ {
+			index := int32(i*7919) % s.text.Len(api_pb.PositionUnit_CODE_POINTS)
+			_, err = s.apply("", &pendingOp{
+				userID:  "user",
+				ops:     []*api_pb.Operation{{Type: api_pb.OpType_INSERT, Index: index, Text: "x", Len: 1}},
+				version: s.history.Version(),
+				unit:    api_pb.PositionUnit_CODE_POINTS,
+			})
+		}
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+}
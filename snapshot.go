@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"github.com/ssau-fiit/cloudocs-api/database"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"strconv"
+	"time"
+)
+
+// The latest snapshot of a document is kept in the snapshots.<id> hash. The
+// operation log is only ever trimmed up to the latest snapshot, so the
+// snapshot plus the log tail after it always add up to the current text.
+
+type snapshot struct {
+	Version int32
+	Text    string
+	Time    time.Time
+}
+
+func snapshotKey(docID string) string {
+	return fmt.Sprintf("snapshots.%v", docID)
+}
+
+// loadSnapshot returns the latest snapshot of the document, or nil if none was
+// taken yet.
+func loadSnapshot(ctx context.Context, docID string) (*snapshot, error) {
+	res, err := database.Database().HGetAll(ctx, snapshotKey(docID)).Result()
+	if err != nil || len(res) == 0 {
+		return nil, err
+	}
+
+	version, err := strconv.ParseInt(res["version"], 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	millis, err := strconv.ParseInt(res["time"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		Version: int32(version),
+		Text:    res["text"],
+		Time:    time.UnixMilli(millis),
+	}, nil
+}
+
+// saveSnapshot stores text as the snapshot at the latest version of history and
+// compacts the operation log, keeping operations that are newer than either the
+// snapshot or the retention window.
+func saveSnapshot(ctx context.Context, docID, text string, history *ot.History) error {
+	version := history.Version()
+	keep := retained(history)
+
+	_, err := database.Database().TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, snapshotKey(docID),
+			"version", version,
+			"text", text,
+			"time", time.Now().UnixMilli(),
+		)
+		pipe.XTrimMinID(ctx, opsKey(docID), fmt.Sprintf("%v-0", keep))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	history.Compact(keep - 1)
+	return nil
+}
+
+// retained returns the oldest version of history whose revision is still within
+// the retention window, or the version following the latest one if none is.
+func retained(history *ot.History) int32 {
+	revs, _ := history.Since(history.Base())
+	for _, rev := range revs {
+		if time.Since(rev.Time) < opsRetention {
+			return rev.Version
+		}
+	}
+	return history.Version() + 1
+}
+
+// loadDocument rebuilds the document text from its latest snapshot and the
+// operations applied after it. The returned history holds every operation still
+// kept in the log. Documents without a snapshot get one from texts.<id>, which
+// was kept in sync with the log before snapshots existed.
+func loadDocument(ctx context.Context, docID string) (*ot.Buffer, *ot.History, error) {
+	text, history, snapshotted, err := readDocument(ctx, docID)
+	if err != nil || snapshotted {
+		return text, history, err
+	}
+	return text, history, saveSnapshot(ctx, docID, text.String(), history)
+}
+
+// readDocument is loadDocument without writing anything, for readers outside
+// the session of the document. snapshotted is false if the document has no
+// snapshot yet.
+func readDocument(ctx context.Context, docID string) (*ot.Buffer, *ot.History, bool, error) {
+	snap, err := loadSnapshot(ctx, docID)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var base int32
+	if snap != nil {
+		base = snap.Version
+	}
+	history, err := loadHistory(ctx, docID, base)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if snap == nil {
+		text, err := database.Database().Get(ctx, fmt.Sprintf("texts.%v", docID)).Result()
+		if err != nil {
+			return nil, nil, false, err
+		}
+		return ot.NewBuffer(text), history, false, nil
+	}
+
+	revs, err := history.Since(snap.Version)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	text := ot.NewBuffer(snap.Text)
+	for _, rev := range revs {
+		if _, err := text.Apply(rev.Ops, api_pb.PositionUnit_CODE_POINTS); err != nil {
+			return nil, nil, false, fmt.Errorf("replaying version %v: %w", rev.Version, err)
+		}
+	}
+	return text, history, true, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSnapshotAndLogTail(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := dialTestClient(t, srv, doc.ID, "user")
+	defer c.conn.Close()
+	c.insert(0, "X")
+	c.delete(1, 1)
+	compact(t, doc)
+	// these only live in the log tail after the snapshot
+	c.insert(3, "Y")
+	c.insert(0, "Z")
+
+	if entries, _ := redisServer.Stream(opsKey(doc.ID)); len(entries) != 2 {
+		t.Errorf("the log holds %v entries after compaction, want the 2 of the tail", len(entries))
+	}
+	text, history, snapshotted, err := readDocument(context.Background(), doc.ID)
+	if err != nil || !snapshotted {
+		t.Fatalf("got snapshotted %v, %v", snapshotted, err)
+	}
+	if text.String() != c.current() || text.String() != "ZXbcY" {
+		t.Errorf("loaded %q, the client has %q", text.String(), c.current())
+	}
+	if history.Base() != 2 || history.Version() != 4 {
+		t.Errorf("loaded versions %v to %v, want 2 to 4", history.Base(), history.Version())
+	}
+}
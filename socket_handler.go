@@ -1,17 +1,19 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/websocket"
-	"github.com/mitchellh/mapstructure"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
-	"github.com/ssau-fiit/cloudocs-api/database"
+	"github.com/ssau-fiit/cloudocs-api/common/uuid"
 	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -24,77 +26,175 @@ var (
 		EnumsAsInts:  false,
 		EmitDefaults: false,
 	}
-	opsList = operationsList{
-		mu:  make(map[string]*sync.Mutex),
-		ops: make(map[string][]*api_pb.Operation),
-	}
-	clients = sync.Map{}
+	sessions = newHub()
 )
 
-type operationsList struct {
-	mu  map[string]*sync.Mutex
-	ops map[string][]*api_pb.Operation
+type client struct {
+	id   string
+	name string
+	// connID tells apart several connections of the same user
+	connID string
+	conn   transport
+	codec  codec
+	unit   api_pb.PositionUnit
+	// out queues the events to write, only the writer goroutine writes to conn
+	out  chan []byte
+	quit chan struct{}
+	once sync.Once
+
+	// version and capabilities are the ones negotiated with the client. hello
+	// is the request id of its Hello, answered by Init or Resume.
+	version      int32
+	capabilities []api_pb.Capability
+	hello        string
+
+	participant *api_pb.Participant
+	// cursor positions are counted in code points
+	cursor *api_pb.Cursor
+	// resume is the version a reconnecting client had seen
+	resume *int32
+}
+
+func newClient(id, name string, conn transport, c codec, unit api_pb.PositionUnit) *client {
+	return &client{
+		id:     id,
+		name:   name,
+		connID: uuid.Must(uuid.NewV4()).String(),
+		conn:   conn,
+		codec:  c,
+		unit:   unit,
+		out:    make(chan []byte, sendQueueSize),
+		quit:   make(chan struct{}),
+
+		// clients that don't send Hello speak version 1
+		version:      1,
+		capabilities: baseline(c),
+	}
 }
 
-func (o *operationsList) Add(docID string, op *api_pb.Operation) error {
-	o.mu[docID].Lock()
-	defer o.mu[docID].Unlock()
+// send queues a single event for the client. A client too slow to keep up
+// with its queue is disconnected.
+func (cl *client) send(t api_pb.Event_EventType, msg proto.Message) {
+	cl.reply("", t, msg)
+}
 
-	db := database.Database()
-	text, err := db.Get(context.Background(), fmt.Sprintf("texts.%v", docID)).Result()
+// reply queues an event answering the client request with the given id.
+func (cl *client) reply(requestID string, t api_pb.Event_EventType, msg proto.Message) {
+	data, err := cl.codec.encode(requestID, t, msg)
 	if err != nil {
-		log.Error().Err(err).Msg("error getting document text")
-		return err
+		log.Error().Err(err).Str("type", t.String()).Msg("error encoding event")
+		return
 	}
-	textBytes := []byte(text)
 
-	conflictOps := make([]*api_pb.Operation, 0, len(o.ops[docID]))
-	for _, operation := range o.ops[docID] {
-		if operation.Version == op.Version {
-			conflictOps = append(conflictOps, operation)
-		}
+	select {
+	case cl.out <- data:
+	case <-cl.quit:
+	default:
+		log.Warn().Str("user", cl.id).Msg("send queue full, disconnecting client")
+		cl.close()
 	}
+}
 
-	if len(conflictOps) > 0 {
-		for _, conflictOp := range conflictOps {
-			switch true {
-			case op.Index < conflictOp.Index:
-				switch op.Type {
-				case api_pb.OpType_INSERT:
-					conflictOp.Index += op.Len
-				case api_pb.OpType_DELETE:
-					conflictOp.Index -= op.Len
-				}
-
-			case op.Index > conflictOp.Index:
-				fallthrough
-			default:
-				switch conflictOp.Type {
-				case api_pb.OpType_INSERT:
-					op.Index += conflictOp.Len
-				case api_pb.OpType_DELETE:
-					op.Index -= conflictOp.Len
-				}
-			}
-		}
+// close disconnects the client. Its read loop fails right after, which removes
+// the client from its session.
+func (cl *client) close() {
+	cl.once.Do(func() {
+		close(cl.quit)
+		cl.conn.close()
+	})
+}
+
+// end disconnects the client once the events queued so far are written.
+func (cl *client) end() {
+	select {
+	case cl.out <- nil:
+	case <-cl.quit:
+	default:
+		cl.close()
 	}
+}
+
+// writer writes the queued events to the connection and pings the client
+// every pingInterval until the client is closed.
+func (cl *client) writer() {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	defer cl.close()
 
-	switch op.Type {
-	case api_pb.OpType_INSERT:
-		tmp := text[:op.Index]
-		textBytes = []byte(tmp + op.Text + string(textBytes[op.Index:]))
-	case api_pb.OpType_DELETE:
-		if int(op.Index) == len(textBytes) {
-			textBytes = textBytes[:op.Index-op.Len]
-		} else {
-			textBytes = append(textBytes[:op.Index-op.Len+1], textBytes[op.Index+1:]...)
+	for {
+		var err error
+		select {
+		case msg := <-cl.out:
+			if msg == nil {
+				// queued by end
+				return
+			}
+			err = cl.conn.write(msg)
+		case <-ticker.C:
+			err = cl.conn.ping()
+		case <-cl.quit:
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("user", cl.id).Msg("failed to write message to client")
+			return
 		}
 	}
-	o.ops[docID] = append(o.ops[docID], op)
+}
+
+// transport carries the events of a client.
+type transport interface {
+	// write writes a single encoded event.
+	write(data []byte) error
+	// ping checks the other end is still there.
+	ping() error
+	close()
+}
+
+// socket is the transport of websocket clients.
+type socket struct {
+	conn        *websocket.Conn
+	messageType int
+}
+
+func (s *socket) write(data []byte) error {
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return s.conn.WriteMessage(s.messageType, data)
+}
 
-	_, err = db.Set(context.Background(), fmt.Sprintf("texts.%v", docID), string(textBytes), 0).Result()
+func (s *socket) ping() error {
+	s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return s.conn.WriteMessage(websocket.PingMessage, nil)
+}
+
+func (s *socket) close() {
+	s.conn.Close()
+}
+
+// parseUnit parses the position unit a client asked for. Positions are counted
+// in code points by default.
+func parseUnit(s string) (api_pb.PositionUnit, error) {
+	if s == "" {
+		return api_pb.PositionUnit_CODE_POINTS, nil
+	}
+	v, ok := api_pb.PositionUnit_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("unknown position unit %q", s)
+	}
+	return api_pb.PositionUnit(v), nil
+}
 
-	return err
+// parseResume parses the version a reconnecting client had seen, if any.
+func parseResume(s string) (*int32, error) {
+	if s == "" {
+		return nil, nil
+	}
+	version, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	resume := int32(version)
+	return &resume, nil
 }
 
 func handleSocket(c *gin.Context) {
@@ -106,141 +206,84 @@ func handleSocket(c *gin.Context) {
 	clientID := c.GetHeader("X-Cloudocs-ID")
 	if clientID == "" {
 		c.AbortWithStatus(http.StatusUnauthorized)
+		return
 	}
-
-	if _, ok := opsList.mu[docID]; !ok {
-		opsList.mu[docID] = &sync.Mutex{}
+	name := c.Query("name")
+	if name == "" {
+		name = clientID
+	}
+	unit, err := parseUnit(c.Query("unit"))
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
 	}
-	if _, ok := opsList.ops[docID]; !ok {
-		opsList.ops[docID] = []*api_pb.Operation{}
+	resume, err := parseResume(c.Query("version"))
+	if err != nil {
+		c.AbortWithStatus(http.StatusBadRequest)
+		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
-	if exists, err := database.Database().
-		Exists(ctx, fmt.Sprintf("documents.%v", docID)).
-		Result(); exists == 0 || err != nil {
+	doc, err := getDocument(ctx, docID)
+	if err == redis.Nil {
 		c.AbortWithStatus(http.StatusNotFound)
 		return
 	}
-
-	// Document info
-	rawRes, err := database.Database().HGetAll(ctx, fmt.Sprintf("documents.%v", docID)).Result()
 	if err != nil {
 		log.Error().Err(err).Msg("error getting document")
 		c.AbortWithStatus(http.StatusInternalServerError)
 		return
 	}
-	var doc Document
-	err = mapstructure.Decode(rawRes, &doc)
-	if err != nil {
-		log.Error().Err(err).Msg("error decoding document")
-		c.AbortWithStatus(http.StatusInternalServerError)
-		return
-	}
-
-	text, err := database.Database().Get(ctx, fmt.Sprintf("texts.%v", docID)).Result()
-	if err != nil {
-		log.Error().Err(err).Msg("error getting document text")
-		c.AbortWithStatus(http.StatusInternalServerError)
-		return
-	}
 
 	// upgrading connection to websocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("error upgrading connection")
 		c.AbortWithStatus(http.StatusUpgradeRequired)
+		return
+	}
+	codec := codecFor(conn.Subprotocol())
+	cl := newClient(clientID, name, &socket{conn, codec.messageType()}, codec, unit)
+	cl.resume = resume
+	defer cl.close()
+	if err := cl.handshake(conn); err != nil {
+		log.Error().Err(err).Str("user", clientID).Msg("handshake failed")
+		return
 	}
-	defer conn.Close()
+	go cl.writer()
 
-	// storing client connection locally
-	clients.Store(clientID, conn)
-	defer clients.Delete(clientID)
+	// a client that stops answering pings is considered gone
+	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
 
-	// sending initial message containing document info and text
-	var lastVer int32 = 0
-	if len(opsList.ops[docID]) > 0 {
-		lastVer = opsList.ops[docID][len(opsList.ops[docID])-1].Version
-	}
-	initMsg := &api_pb.Init{
-		DocumentName: doc.Name,
-		Text:         text,
-		LastVersion:  lastVer,
-	}
-	initJson, _ := encoder.MarshalToString(initMsg)
-	ev := &api_pb.Event{
-		Type:  api_pb.Event_INIT,
-		Event: []byte(initJson),
-	}
-	evJson, _ := encoder.MarshalToString(ev)
-	conn.WriteMessage(websocket.TextMessage, []byte(evJson))
+	// registering client connection in the document session
+	session := sessions.join(doc, cl)
+	defer sessions.leave(session, cl)
 
 	for {
 		// Read incoming message from client
-		mt, msg, err := conn.ReadMessage()
+		_, msg, err := conn.ReadMessage()
 		if err != nil {
 			log.Error().Err(err).Msg("failed to read message from client")
 			return
 		}
+		conn.SetReadDeadline(time.Now().Add(pongTimeout))
 
-		var ev api_pb.Event
-		err = decoder.Unmarshal(bytes.NewReader(msg), &ev)
+		requestID, payload, err := cl.codec.decode(msg)
 		if err != nil {
-			log.Error().Err(err).Msg("error unmarshaling message")
+			log.Error().Err(err).Msg("error decoding event")
+			cl.fail(requestID, err, nil)
 			continue
 		}
-
-		switch ev.Type {
-		case api_pb.Event_OPERATION:
-			var op api_pb.Operation
-			err = decoder.Unmarshal(bytes.NewReader(ev.Event), &op)
-			if err != nil {
-				log.Error().Err(err).Msg("error unmarshaling operation")
-				continue
-			}
-
-			err = opsList.Add(docID, &op)
-			if err != nil {
-				log.Error().Err(err).Msg("error while doing operation")
-			}
-			log.Debug().Interface("operation", op).Msg("operation received")
-
-			ack := &api_pb.OperationAck{
-				LastVersion: opsList.ops[docID][len(opsList.ops[docID])-1].Version,
-			}
-			ackStr, _ := encoder.MarshalToString(ack)
-
-			resEv := &api_pb.Event{
-				Type:  api_pb.Event_OPERATION_ACK,
-				Event: []byte(ackStr),
-			}
-			res, _ := encoder.MarshalToString(resEv)
-
-			conn.WriteMessage(mt, []byte(res))
-
-			broadcastOperations([]*api_pb.Operation{&op}, clientID)
+		if !cl.accepts(payload) {
+			cl.fail(requestID, fmt.Errorf("%w: capability not negotiated", errUnsupportedEvent), nil)
+			continue
 		}
-	}
-}
 
-func broadcastOperations(ops []*api_pb.Operation, except string) error {
-	for _, op := range ops {
-		clients.Range(func(clientID, conn any) bool {
-			if clientID.(string) == except {
-				return true
-			}
-			opJson, _ := encoder.MarshalToString(op)
-			ev := &api_pb.Event{
-				Type:  api_pb.Event_OPERATION,
-				Event: []byte(opJson),
-			}
-			evJson, _ := encoder.MarshalToString(ev)
-			conn.(*websocket.Conn).WriteMessage(websocket.TextMessage, []byte(evJson))
-			return true
-		})
+		session.submit(cl, requestID, payload)
 	}
-
-	return nil
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// syncOffline commits the operations a client made offline on top of the
+// version of r as a single revision made by userID, and calls done with
+// everything the client needs to catch up once the sequencer applied them.
+func (s *session) syncOffline(userID string, r *api_pb.SyncRequest, done func(*api_pb.SyncResponse, error)) {
+	if s.history == nil {
+		done(nil, errNotLoaded)
+		return
+	}
+	if len(r.Operations) == 0 {
+		done(s.synced(r, nil))
+		return
+	}
+	if rev := s.history.Find(userID, r.Id); rev != nil {
+		done(s.synced(r, rev))
+		return
+	}
+
+	s.sequence(r.Id, &pendingOp{
+		userID:  userID,
+		ops:     r.Operations,
+		version: r.Version,
+		unit:    r.PositionUnit,
+		done: func(rev *ot.Revision, err error) {
+			if err != nil {
+				done(nil, err)
+				return
+			}
+			done(s.synced(r, rev))
+		},
+	})
+}
+
+// synced answers r with rev, the revision its operations were committed in,
+// if any, and the revisions made by others in between.
+func (s *session) synced(r *api_pb.SyncRequest, rev *ot.Revision) (*api_pb.SyncResponse, error) {
+	res := &api_pb.SyncResponse{
+		LastVersion: s.history.Version(),
+		Checksum:    s.text.Checksum(),
+	}
+	if rev != nil {
+		res.LastVersion = rev.Version
+		res.Operations = rev.In(r.PositionUnit)
+		res.Checksum = rev.Checksum
+	}
+
+	revs, err := s.history.Since(r.Version)
+	if errors.Is(err, ot.ErrCompacted) {
+		// too far behind to catch up operation by operation
+		res.LastVersion = s.history.Version()
+		res.Checksum = s.text.Checksum()
+		res.Text = s.text.String()
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, missed := range revs {
+		if rev != nil && missed.Version >= rev.Version {
+			break
+		}
+		res.Missed = append(res.Missed, missed.In(r.PositionUnit)...)
+	}
+	return res, nil
+}
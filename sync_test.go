@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// syncDocument sends the sync request body for the document and decodes the
+// response.
+func syncDocument(t *testing.T, srv *httptest.Server, docID, body string) *api_pb.SyncResponse {
+	t.Helper()
+	code, data := request(t, srv, http.MethodPost, "/api/v1/documents/"+docID+"/sync", "offline", body)
+	if code != http.StatusOK {
+		t.Fatalf("sync %s: got %v %s", body, code, data)
+	}
+	var res api_pb.SyncResponse
+	if err := decoder.Unmarshal(bytes.NewReader(data), &res); err != nil {
+		t.Fatal(err)
+	}
+	return &res
+}
+
+// texts returns the texts of ops.
+func texts(ops []*api_pb.Operation) []string {
+	var texts []string
+	for _, op := range ops {
+		texts = append(texts, op.Text)
+	}
+	return texts
+}
+
+func TestSync(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := dialTestClient(t, srv, doc.ID, "online")
+	defer c.conn.Close()
+	c.insert(0, "X")
+	c.insert(4, "Y")
+
+	// made offline on top of version 0
+	body := `{"version": 0, "id": "offline-1", "operations": [{"type": "INSERT", "index": 1, "text": "Z", "len": 1}]}`
+	res := syncDocument(t, srv, doc.ID, body)
+	if res.LastVersion != 3 || len(res.Operations) != 1 || res.Operations[0].Index != 2 {
+		t.Errorf("got %v, want the insert moved after X at version 3", res.Operations)
+	}
+	if missed := texts(res.Missed); len(missed) != 2 || missed[0] != "X" || missed[1] != "Y" {
+		t.Errorf("missed %v", missed)
+	}
+	if res.Text != "" || res.Checksum != ot.Checksum("XaZbcY") {
+		t.Errorf("got the text %q and checksum %v", res.Text, res.Checksum)
+	}
+	if !waitFor(func() bool { return c.current() == "XaZbcY" }) {
+		t.Errorf("the online client has %q", c.current())
+	}
+
+	// a retry gets the same answer without applying the operations again
+	retry := syncDocument(t, srv, doc.ID, body)
+	if retry.LastVersion != 3 || len(retry.Operations) != 1 || retry.Operations[0].Index != 2 || len(retry.Missed) != 2 {
+		t.Errorf("retry got %v", retry)
+	}
+	if text := waitText(doc.ID, "XaZbcY"); text != "XaZbcY" {
+		t.Errorf("server has %q", text)
+	}
+}
+
+func TestSyncCompacted(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := dialTestClient(t, srv, doc.ID, "online")
+	defer c.conn.Close()
+	c.insert(0, "X")
+	c.insert(4, "Y")
+	compact(t, doc)
+
+	// the revisions since version 0 are gone, so the client gets the text
+	res := syncDocument(t, srv, doc.ID, `{"version": 0}`)
+	if res.LastVersion != 2 || res.Text != "XabcY" || len(res.Missed) != 0 {
+		t.Errorf("got %v", res)
+	}
+}
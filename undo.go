@@ -0,0 +1,113 @@
+package main
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+// undoStack holds the versions of the revisions a user can undo and of the
+// undos the user can redo, latest last. Every replica keeps the stacks from
+// the revisions it sees, so they only cover the time the document is open.
+type undoStack struct {
+	undo []int32
+	redo []int32
+}
+
+// record updates the stacks of the user that made rev.
+func (s *session) record(rev *ot.Revision) {
+	if rev.UserID == "" {
+		return
+	}
+	st, ok := s.undo[rev.UserID]
+	if !ok {
+		st = &undoStack{}
+		s.undo[rev.UserID] = st
+	}
+
+	switch {
+	case rev.Reverts != 0 && top(st.undo) == rev.Reverts:
+		st.undo = st.undo[:len(st.undo)-1]
+		st.redo = push(st.redo, rev.Version)
+	case rev.Reverts != 0 && top(st.redo) == rev.Reverts:
+		st.redo = st.redo[:len(st.redo)-1]
+		st.undo = push(st.undo, rev.Version)
+	case len(rev.Ops) > 0:
+		st.undo = push(st.undo, rev.Version)
+		st.redo = nil
+	}
+}
+
+// revertible reports whether the revision with the given version is the next
+// one userID would undo or redo.
+func (s *session) revertible(userID string, version int32) bool {
+	st, ok := s.undo[userID]
+	return ok && (top(st.undo) == version || top(st.redo) == version)
+}
+
+// handleUndo reverts the latest revision of the client's user that is not
+// undone yet, or the latest undo if redo is set. The revision answers
+// requestID.
+func (s *session) handleUndo(cl *client, requestID string, redo bool) {
+	var version int32
+	if st, ok := s.undo[cl.id]; ok {
+		// revisions older than the kept history can't be reverted anymore
+		st.undo = compacted(st.undo, s.history.Base())
+		st.redo = compacted(st.redo, s.history.Base())
+		if redo {
+			version = top(st.redo)
+		} else {
+			version = top(st.undo)
+		}
+	}
+	rev := s.history.At(version)
+	if rev == nil {
+		cl.fail(requestID, errNothingToUndo, nil)
+		return
+	}
+
+	s.sequence("", &pendingOp{
+		client:       cl,
+		userID:       cl.id,
+		connectionID: cl.connID,
+		ops:          ot.Invert(rev.Ops),
+		version:      rev.Version,
+		unit:         api_pb.PositionUnit_CODE_POINTS,
+		reverts:      rev.Version,
+		done: func(rev *ot.Revision, err error) {
+			if err != nil {
+				log.Error().Err(err).Msg("error while reverting revision")
+				s.fail(cl, requestID, err, nil)
+				return
+			}
+			cl.revision(requestID, rev)
+		},
+	})
+}
+
+// top returns the latest version of a stack, or 0 if it is empty.
+func top(stack []int32) int32 {
+	if len(stack) == 0 {
+		return 0
+	}
+	return stack[len(stack)-1]
+}
+
+// push adds version to a stack, dropping the oldest ones beyond undoDepth.
+func push(stack []int32, version int32) []int32 {
+	stack = append(stack, version)
+	if len(stack) > undoDepth {
+		stack = append([]int32(nil), stack[len(stack)-undoDepth:]...)
+	}
+	return stack
+}
+
+// compacted drops the versions up to base from a stack.
+func compacted(stack []int32, base int32) []int32 {
+	for i, version := range stack {
+		if version > base {
+			return stack[i:]
+		}
+	}
+	return nil
+}
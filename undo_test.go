@@ -0,0 +1,47 @@
+package main
+
+import (
+	"github.com/golang/protobuf/proto"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+	"testing"
+)
+
+func TestUndoKeepsOthersEdits(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	a := dialTestClient(t, srv, doc.ID, "a")
+	defer a.conn.Close()
+	b := dialTestClient(t, srv, doc.ID, "b")
+	defer b.conn.Close()
+
+	a.insert(0, "hello")
+	if !waitFor(func() bool { return b.current() == "helloabc" }) {
+		t.Fatalf("b has %q", b.current())
+	}
+	// b types inside the text a is about to undo
+	b.insert(2, "X")
+	if !waitFor(func() bool { return a.current() == "heXlloabc" }) {
+		t.Fatalf("a has %q", a.current())
+	}
+
+	for _, step := range []struct {
+		event api_pb.Event_EventType
+		msg   proto.Message
+		want  string
+	}{
+		{api_pb.Event_UNDO, &api_pb.Undo{}, "Xabc"},
+		{api_pb.Event_REDO, &api_pb.Redo{}, "heXlloabc"},
+	} {
+		a.write(step.event, step.msg)
+		for _, c := range []*testClient{a, b} {
+			if !waitFor(func() bool { return c.current() == step.want }) {
+				t.Fatalf("after %v a client has %q, want %q", step.event, c.current(), step.want)
+			}
+		}
+		if text := waitText(doc.ID, step.want); text != step.want {
+			t.Errorf("after %v the server has %q, want %q", step.event, text, step.want)
+		}
+	}
+}
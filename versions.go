@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/ssau-fiit/cloudocs-api/ot"
+	api_pb "github.com/ssau-fiit/cloudocs-api/proto/api"
+)
+
+var errNotLoaded = errors.New("document could not be loaded")
+
+// textAt returns the text of the document as of version, undoing everything
+// applied after it. Versions older than the kept log are not available.
+func textAt(ctx context.Context, docID string, version int32) (string, error) {
+	text, history, _, err := readDocument(ctx, docID)
+	if err != nil {
+		return "", err
+	}
+	revs, err := history.Since(version)
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(revs) - 1; i >= 0; i-- {
+		if _, err := text.Apply(ot.Invert(revs[i].Ops), api_pb.PositionUnit_CODE_POINTS); err != nil {
+			return "", err
+		}
+	}
+	return text.String(), nil
+}
+
+// replace turns the document text into target with a single revision made by
+// userID and sends it to every client. done is called with the version of the
+// revision once the sequencer applied it, or with the current version if the
+// text already was target.
+func (s *session) replace(userID, target string, done func(version int32, err error)) {
+	if s.history == nil {
+		done(0, errNotLoaded)
+		return
+	}
+	s.sequence("", &pendingOp{
+		userID:  userID,
+		ops:     ot.Diff(s.text.String(), target),
+		version: s.history.Version(),
+		unit:    api_pb.PositionUnit_CODE_POINTS,
+		done: func(rev *ot.Revision, err error) {
+			switch {
+			case err != nil:
+				done(0, err)
+			case rev == nil:
+				done(s.history.Version(), nil)
+			default:
+				done(rev.Version, nil)
+			}
+		},
+	})
+}
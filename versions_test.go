@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestVersions(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := dialTestClient(t, srv, doc.ID, "user")
+	defer c.conn.Close()
+	c.insert(0, "X")
+	c.delete(1, 1)
+	c.insert(3, "Y")
+
+	for version, want := range []string{"abc", "Xabc", "Xbc", "XbcY"} {
+		code, body := request(t, srv, http.MethodGet, fmt.Sprintf("/api/v1/documents/%v/versions/%v", doc.ID, version), "user", "")
+		var res struct {
+			Version int32
+			Text    string
+		}
+		json.Unmarshal(body, &res)
+		if code != http.StatusOK || res.Text != want {
+			t.Errorf("version %v: got %v %q, want %q", version, code, res.Text, want)
+		}
+	}
+	if code, _ := request(t, srv, http.MethodGet, "/api/v1/documents/"+doc.ID+"/versions/9", "user", ""); code != http.StatusNotFound {
+		t.Errorf("unknown version: got status %v", code)
+	}
+
+	code, body := request(t, srv, http.MethodPost, "/api/v1/documents/"+doc.ID+"/restore", "admin", `{"version": 1}`)
+	var res struct{ Version int32 }
+	json.Unmarshal(body, &res)
+	if code != http.StatusOK || res.Version != 4 {
+		t.Fatalf("restore: got %v %s", code, body)
+	}
+	// the connected client gets the restored text as a new revision
+	if !waitFor(func() bool { return c.current() == "Xabc" }) {
+		t.Errorf("client has %q after the restore", c.current())
+	}
+	if text := waitText(doc.ID, "Xabc"); text != "Xabc" {
+		t.Errorf("server has %q after the restore", text)
+	}
+}
+
+func TestRestoreCurrentVersion(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	doc := newTestDocument(t, "abc")
+
+	c := dialTestClient(t, srv, doc.ID, "user")
+	defer c.conn.Close()
+	c.insert(0, "X")
+
+	// the text already is the one of version 1, so no revision is made
+	code, body := request(t, srv, http.MethodPost, "/api/v1/documents/"+doc.ID+"/restore", "admin", `{"version": 1}`)
+	var res struct{ Version int32 }
+	json.Unmarshal(body, &res)
+	if code != http.StatusOK || res.Version != 1 {
+		t.Fatalf("restore: got %v %s", code, body)
+	}
+	if code, _ := request(t, srv, http.MethodGet, "/api/v1/documents/"+doc.ID+"/versions/2", "user", ""); code != http.StatusNotFound {
+		t.Errorf("restoring the current text made version 2, got status %v", code)
+	}
+}
+
+func TestTextAtWritesNothing(t *testing.T) {
+	doc := newTestDocument(t, "abc")
+	text, err := textAt(context.Background(), doc.ID, 0)
+	if err != nil || text != "abc" {
+		t.Fatalf("got %q, %v", text, err)
+	}
+	if redisServer.Exists(snapshotKey(doc.ID)) {
+		t.Error("reading a version took a snapshot")
+	}
+}